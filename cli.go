@@ -0,0 +1,575 @@
+package main
+
+import (
+	"basketball/config"
+	"basketball/db"
+	"basketball/nba"
+
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	flag "github.com/spf13/pflag"
+)
+
+// ExitCode is what the process exits with. Scripts can tell a bad invocation
+// from a broken database from everything else.
+type ExitCode int
+
+const (
+	ExitOK       ExitCode = 0
+	ExitFailure  ExitCode = 1
+	ExitUsage    ExitCode = 2
+	ExitDatabase ExitCode = 3
+)
+
+// exitError carries the exit code an error should end the process with.
+// Errors without one exit with ExitFailure.
+type exitError struct {
+	code ExitCode
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+func usageErrorf(format string, a ...any) error {
+	return &exitError{ExitUsage, fmt.Errorf(format, a...)}
+}
+
+func exitCodeOf(err error) ExitCode {
+	if err == nil {
+		return ExitOK
+	}
+	var e *exitError
+	if errors.As(err, &e) {
+		return e.code
+	}
+	return ExitFailure
+}
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+// commands is filled in by init since the help command lists it.
+var commands []command
+
+func init() {
+	commands = []command{
+		{"statline", "print players' statlines for the selected games as text, json, csv or markdown", statlineCmd},
+		{"video", "make highlight reels of a player's selected games or of a manifest's reels", videoCmd},
+		{"team-reel", "make and upload a reel for every player on a team in its selected games", teamReelCmd},
+		{"watch", "make a team's reels as soon as each of its games is final", watchCmd},
+		{"sync", "store every player and team in a league", syncCmd},
+		{"upload", "upload rendered reels that haven't been uploaded yet", uploadCmd},
+		{"jobs", "list, retry or cancel reel jobs", jobsCmd},
+		{"list", "list what's in the database", listCmd},
+		{"games", "list the games on a date or in a season", gamesCmd},
+		{"db", "run or roll back database migrations (also migrate)", dbCmd},
+		{"config", "show the settings in effect and where they came from", configCmd},
+		{"serve", "serve statlines, jobs and uploads over http", serveCmd},
+		{"help", "show help for a command", helpCmd},
+	}
+}
+
+func main() {
+	os.Exit(int(run(os.Args[1:])))
+}
+
+// run is main without os.Exit so deferred cleanup happens.
+func run(args []string) ExitCode {
+	defer func() {
+		if store != nil {
+			store.Close()
+		}
+	}()
+
+	if len(args) == 0 {
+		printUsage()
+		return ExitUsage
+	}
+	name := args[0]
+	if name == "-h" || name == "--help" {
+		printUsage()
+		return ExitOK
+	}
+	cmd, ok := findCommand(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		printUsage()
+		return ExitUsage
+	}
+	if err := cmd.run(args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitCodeOf(err)
+	}
+	return ExitOK
+}
+
+// aliases are other names commands answer to, e.g. from before they were
+// renamed, so scripts written against the old names keep working.
+var aliases = map[string]string{
+	"migrate": "db",
+}
+
+func findCommand(name string) (command, bool) {
+	if alias, ok := aliases[name]; ok {
+		name = alias
+	}
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "usage: basketball <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, `run "basketball <command> --help" for a command's flags`)
+}
+
+func helpCmd(args []string) error {
+	if len(args) == 0 {
+		printUsage()
+		return nil
+	}
+	cmd, ok := findCommand(args[0])
+	if !ok || cmd.name == "help" {
+		return usageErrorf("unknown command %q", args[0])
+	}
+	return cmd.run([]string{"--help"})
+}
+
+// newFlagSet makes a command's flag set. usage is what follows the command
+// name in the usage line and details is printed after the summary, e.g. a
+// list of subcommands.
+func newFlagSet(name, usage, details string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.StringVar(&configFile, "config", "", "config file to read (default $BASKETBALL_CONFIG or the user config dir's basketball/config.yaml)")
+	flags.StringArrayVar(&configSets, "set", nil, "override a config setting, e.g. --set output_dir=~/reels (repeatable)")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: basketball %s %s\n", name, usage)
+		if cmd, ok := findCommand(name); ok {
+			fmt.Fprintf(os.Stderr, "\n%s\n", cmd.summary)
+		}
+		if details != "" {
+			fmt.Fprintf(os.Stderr, "\n%s\n", details)
+		}
+		if flags.HasFlags() {
+			fmt.Fprintf(os.Stderr, "\nflags:\n%s", flags.FlagUsages())
+		}
+	}
+	return flags
+}
+
+// configFile and configSets are set by every command's --config and --set.
+var configFile string
+var configSets []string
+
+// parseFlags parses a command's flags and loads the config they point at.
+// help is true when --help was asked for and the command should return
+// without doing anything.
+func parseFlags(flags *flag.FlagSet, args []string) (help bool, err error) {
+	if err := flags.Parse(args); errors.Is(err, flag.ErrHelp) {
+		return true, nil
+	} else if err != nil {
+		return false, &exitError{ExitUsage, err}
+	}
+	if err := config.LoadConfig(configFile, configSets); err != nil {
+		return false, &exitError{ExitUsage, err}
+	}
+	nba.SetMaxRequests(config.Requests)
+	return false, nil
+}
+
+// leagueFlag adds --league to a command. Call setLeague with its value once
+// flags are parsed.
+func leagueFlag(flags *flag.FlagSet) *string {
+	return flags.String("league", "", "league to work in: nba, wnba or gleague (default the config's league, nba)")
+}
+
+func setLeague(name string) error {
+	if name == "" {
+		name = config.League
+	}
+	l, err := nba.ParseLeague(name)
+	if err != nil {
+		return &exitError{ExitUsage, err}
+	}
+	league = l
+	return nil
+}
+
+func forceFlag(flags *flag.FlagSet) {
+	flags.BoolVarP(&force, "force", "f", false, "upload even if the upload ledger says the video was already published")
+}
+
+// openStore opens the database and, when migrate is set, brings it up to date
+// and validates it. Commands call it after parsing flags so --help never
+// touches the database.
+func openStore(migrate bool) error {
+	if err := os.MkdirAll(filepath.Dir(config.DatabaseFile), 0755); err != nil {
+		return &exitError{ExitDatabase, err}
+	}
+	var err error
+	if store, err = db.Open(config.DatabaseFile); err != nil {
+		return &exitError{ExitDatabase, err}
+	}
+	if !migrate {
+		return nil
+	}
+	if err := store.MigrateUp(); err != nil {
+		return &exitError{ExitDatabase, err}
+	}
+	if err := store.ValidateMigrations(); err != nil {
+		return &exitError{ExitDatabase, err}
+	}
+	return nil
+}
+
+// playerArg joins the positional args into a player name so names don't need
+// quoting.
+func playerArg(flags *flag.FlagSet) (string, error) {
+	if flags.NArg() == 0 {
+		return "", usageErrorf("%s requires a player name", flags.Name())
+	}
+	return strings.Join(flags.Args(), " "), nil
+}
+
+func statlineCmd(args []string) error {
+	flags := newFlagSet("statline", "[flags] <player name>[, <player name>...]", "Separate players with commas to print several at once.")
+	leagueName := leagueFlag(flags)
+	selector := gameSelectorFlags(flags)
+	formatName := flags.String("format", "text", "output format: text, json, csv or markdown")
+	if help, err := parseFlags(flags, args); help || err != nil {
+		return err
+	}
+	if err := selector.Validate(); err != nil {
+		return err
+	}
+	format, err := ParseStatlineFormat(*formatName)
+	if err != nil {
+		return &exitError{ExitUsage, err}
+	}
+	player, err := playerArg(flags)
+	if err != nil {
+		return err
+	}
+	players := []string{}
+	for _, p := range strings.Split(player, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			players = append(players, p)
+		}
+	}
+	if err := setLeague(*leagueName); err != nil {
+		return err
+	}
+	if err := openStore(true); err != nil {
+		return err
+	}
+	return Statline(players, *selector, format)
+}
+
+func videoCmd(args []string) error {
+	flags := newFlagSet("video", "[flags] <player name>\n       basketball video [flags] --manifest <file>", "Makes one reel per selected game.\n\n"+manifestUsage)
+	leagueName := leagueFlag(flags)
+	selector := gameSelectorFlags(flags)
+	policy := promptFlags(flags)
+	manifest := flags.String("manifest", "", "make the reels listed in a YAML manifest instead of one player's")
+	concurrency := flags.Int("concurrency", 0, "with --manifest, how many reels to work on at once (default the manifest's, or the config's concurrency)")
+	summary := flags.String("summary", "", "with --manifest, where to write the JSON summary (default <manifest>.summary.json)")
+	forceFlag(flags)
+	flags.BoolVar(&noUpload, "no-upload", false, "with --manifest, don't upload even the reels marked for upload")
+	collision := outputFlags(flags)
+	if help, err := parseFlags(flags, args); help || err != nil {
+		return err
+	}
+	if err := setOutput(*collision); err != nil {
+		return err
+	}
+	if err := selector.Validate(); err != nil {
+		return err
+	}
+	if err := setMismatchPolicy(*policy); err != nil {
+		return err
+	}
+	if *manifest != "" {
+		if flags.NArg() > 0 {
+			return usageErrorf("video takes a player name or --manifest, not both")
+		}
+		if err := setLeague(*leagueName); err != nil {
+			return err
+		}
+		if err := openStore(true); err != nil {
+			return err
+		}
+		return RunManifest(*manifest, *concurrency, *summary)
+	}
+	player, err := playerArg(flags)
+	if err != nil {
+		return err
+	}
+	if err := setLeague(*leagueName); err != nil {
+		return err
+	}
+	if err := openStore(true); err != nil {
+		return err
+	}
+	results, err := Video(player, *selector)
+	for _, res := range results {
+		fmt.Println(res.OutputFile)
+		printStatline(res.Game)
+	}
+	return err
+}
+
+func teamReelCmd(args []string) error {
+	flags := newFlagSet("team-reel", "[flags]", "Makes one reel per player per selected game.")
+	leagueName := leagueFlag(flags)
+	team := flags.String("team", "", "team to make reels for, by tricode, name or id (default NYK, NYL or WES for the league)")
+	selector := gameSelectorFlags(flags)
+	forceFlag(flags)
+	policy := promptFlags(flags)
+	flags.BoolVar(&noUpload, "no-upload", false, "stop once the reels are rendered instead of asking to upload them")
+	collision := outputFlags(flags)
+	if help, err := parseFlags(flags, args); help || err != nil {
+		return err
+	}
+	if err := setOutput(*collision); err != nil {
+		return err
+	}
+	if err := selector.Validate(); err != nil {
+		return err
+	}
+	if err := setMismatchPolicy(*policy); err != nil {
+		return err
+	}
+	if err := setLeague(*leagueName); err != nil {
+		return err
+	}
+	if *team == "" {
+		*team = homeTeams[league]
+	}
+	if err := openStore(true); err != nil {
+		return err
+	}
+	return TeamReel(*team, *selector)
+}
+
+func syncCmd(args []string) error {
+	flags := newFlagSet("sync", "[flags]", "")
+	leagueName := leagueFlag(flags)
+	if help, err := parseFlags(flags, args); help || err != nil {
+		return err
+	}
+	if err := setLeague(*leagueName); err != nil {
+		return err
+	}
+	if err := openStore(true); err != nil {
+		return err
+	}
+	if err := scrapeCommonAllPlayers(); err != nil {
+		return err
+	}
+	fmt.Printf("synced %s players and teams\n", league)
+	return nil
+}
+
+func uploadCmd(args []string) error {
+	flags := newFlagSet("upload", "[flags] [job id...]", "With no job ids, every rendered job waiting to be uploaded is uploaded.")
+	forceFlag(flags)
+	if help, err := parseFlags(flags, args); help || err != nil {
+		return err
+	}
+	ids := []int64{}
+	for _, arg := range flags.Args() {
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return usageErrorf("invalid job id %q: %v", arg, err)
+		}
+		ids = append(ids, id)
+	}
+	if err := openStore(true); err != nil {
+		return err
+	}
+
+	jobs := []db.Job{}
+	if len(ids) == 0 {
+		all, err := store.Jobs()
+		if err != nil {
+			return err
+		}
+		for _, j := range all {
+			if j.Stage == db.JobStageUpload && j.Status == db.JobStatusPending {
+				jobs = append(jobs, j)
+			}
+		}
+	}
+	for _, id := range ids {
+		j, err := store.Job(id)
+		if err != nil {
+			return err
+		}
+		if j.Stage != db.JobStageUpload {
+			return fmt.Errorf("job %d is at the %s stage, not ready to upload", j.ID, j.Stage)
+		}
+		jobs = append(jobs, j)
+	}
+	if len(jobs) == 0 {
+		fmt.Println("nothing to upload")
+		return nil
+	}
+
+	failed := 0
+	for _, job := range jobs {
+		if err := runJob(&job, db.JobStageDone); err != nil {
+			fmt.Printf("job %d (%s): %v\n", job.ID, job.PlayerName, err)
+			failed++
+			continue
+		}
+		fmt.Printf("job %d (%s) uploaded\n", job.ID, job.PlayerName)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d uploads failed", failed, len(jobs))
+	}
+	return nil
+}
+
+func jobsCmd(args []string) error {
+	flags := newFlagSet("jobs", "<command> [flags]", jobsUsage)
+	forceFlag(flags)
+	policy := promptFlags(flags)
+	collision := outputFlags(flags)
+	if help, err := parseFlags(flags, args); help || err != nil {
+		return err
+	}
+	if err := setOutput(*collision); err != nil {
+		return err
+	}
+	if err := setMismatchPolicy(*policy); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return usageErrorf("jobs requires a command\n\n%s", jobsUsage)
+	}
+	if err := openStore(true); err != nil {
+		return err
+	}
+	return Jobs(flags.Args())
+}
+
+func listCmd(args []string) error {
+	flags := newFlagSet("list", "<what>", listUsage)
+	if help, err := parseFlags(flags, args); help || err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return usageErrorf("list requires something to list\n\n%s", listUsage)
+	}
+	if err := openStore(true); err != nil {
+		return err
+	}
+	return List(flags.Args())
+}
+
+func gamesCmd(args []string) error {
+	flags := newFlagSet("games", "[flags]", "")
+	date := flags.String("date", "", "list games played on this date (YYYY-MM-DD, default today)")
+	season := flags.String("season", "", "list the whole schedule for a season (e.g. 2024-25) instead of one date")
+	team := flags.String("team", "", "only list games for this team's tricode (e.g. NYK)")
+	status := flags.String("status", "", "only list games that are scheduled, live or final")
+	leagueName := leagueFlag(flags)
+	if help, err := parseFlags(flags, args); help || err != nil {
+		return err
+	}
+	if err := setLeague(*leagueName); err != nil {
+		return err
+	}
+
+	opts := GamesOptions{Date: time.Now(), Season: *season, Team: *team}
+	if *status != "" {
+		s, err := nba.ParseGameStatus(*status)
+		if err != nil {
+			return &exitError{ExitUsage, err}
+		}
+		opts.Status = s
+	}
+	if *date != "" {
+		if *season != "" {
+			return usageErrorf("--date and --season can't be used together")
+		}
+		parsed, err := time.Parse("2006-01-02", *date)
+		if err != nil {
+			return usageErrorf("invalid --date %q, expected YYYY-MM-DD", *date)
+		}
+		opts.Date = parsed
+	}
+	return ListGames(opts)
+}
+
+func dbCmd(args []string) error {
+	flags := newFlagSet("db", "<command>", dbUsage)
+	if help, err := parseFlags(flags, args); help || err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return usageErrorf("db requires a command\n\n%s", dbUsage)
+	}
+	// migrations are what this command manages, so don't run them first
+	if err := openStore(false); err != nil {
+		return err
+	}
+	return DB(flags.Args())
+}
+
+const configUsage = `commands:
+  show          print every setting, its value and where the value came from
+
+Settings are read from the config file, then BASKETBALL_<SETTING> environment
+variables, then --set, each overriding the last.`
+
+func configCmd(args []string) error {
+	flags := newFlagSet("config", "<command>", configUsage)
+	if help, err := parseFlags(flags, args); help || err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return usageErrorf("config requires a command\n\n%s", configUsage)
+	}
+	switch flags.Arg(0) {
+	case "show":
+		if config.File != "" {
+			fmt.Println("config file:", config.File)
+		} else if file, err := config.DefaultFile(); err == nil {
+			fmt.Printf("config file: none (looked for %s)\n", file)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SETTING\tVALUE\tSOURCE")
+		for _, s := range config.Settings() {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", s.Key, s.Value, s.Source)
+		}
+		return w.Flush()
+	default:
+		return usageErrorf("unknown config command %q\n\n%s", flags.Arg(0), configUsage)
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+import "testing"
+
+func TestFindCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{"db", "db", true},
+		{"migrate", "db", true},
+		{"team-reel", "team-reel", true},
+		{"knickerbockers", "", false},
+	}
+	for _, tt := range tests {
+		cmd, ok := findCommand(tt.name)
+		if ok != tt.ok || cmd.name != tt.want {
+			t.Errorf("findCommand(%q) = %q, %v, want %q, %v", tt.name, cmd.name, ok, tt.want, tt.ok)
+		}
+	}
+}
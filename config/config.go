@@ -1,8 +1,14 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
 var DatabaseFile string
@@ -10,14 +16,288 @@ var EndScreenFile string
 var SecretFile string
 var TokenFile string
 
-func LoadConfig() {
-	dir, err := os.Executable()
+// OutputDir is where finished reels are moved to.
+var OutputDir string
+
+// OutputTemplate is where reels are saved under OutputDir, as a text/template
+// filled in with the reel's player, team, game and recipe.
+var OutputTemplate string
+
+// OnCollision is what happens when a reel's output path is taken: number,
+// overwrite or fail.
+var OnCollision string
+
+// Concurrency is how many reels are downloaded, rendered or uploaded at once.
+var Concurrency int
+
+// Requests is how many stats.nba.com requests can be in flight at once.
+var Requests int
+
+// Season is the season to pick games from when none is asked for, e.g.
+// 2024-25. Empty means the current one.
+var Season string
+
+// League is the league commands work in when --league isn't given.
+var League string
+
+// PrivacyStatus is what reels are uploaded as: public, unlisted or private.
+var PrivacyStatus string
+
+// File is the config file that was read, if there was one.
+var File string
+
+// Setting is one configurable value and where its value came from.
+type Setting struct {
+	Key    string
+	Env    string
+	Value  string
+	Source string
+}
+
+type setting struct {
+	key   string
+	value func() string
+	set   func(string) error
+	// file settings fall back to the file next to the executable, where
+	// everything used to live, when it exists and the new default doesn't
+	file   bool
+	source string
+}
+
+func (s *setting) env() string {
+	return "BASKETBALL_" + strings.ToUpper(s.key)
+}
+
+func stringSetting(key string, v *string, file bool) *setting {
+	return &setting{
+		key:   key,
+		value: func() string { return *v },
+		set: func(s string) error {
+			*v = s
+			return nil
+		},
+		file: file,
+	}
+}
+
+func intSetting(key string, v *int) *setting {
+	return &setting{
+		key:   key,
+		value: func() string { return strconv.Itoa(*v) },
+		set: func(s string) error {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 1 {
+				return fmt.Errorf("%s must be a positive number, got %q", key, s)
+			}
+			*v = n
+			return nil
+		},
+	}
+}
+
+func oneOfSetting(key string, v *string, allowed ...string) *setting {
+	return &setting{
+		key:   key,
+		value: func() string { return *v },
+		set: func(s string) error {
+			for _, a := range allowed {
+				if s == a {
+					*v = s
+					return nil
+				}
+			}
+			return fmt.Errorf("%s must be one of %s, got %q", key, strings.Join(allowed, ", "), s)
+		},
+	}
+}
+
+var settings = []*setting{
+	stringSetting("database", &DatabaseFile, true),
+	stringSetting("end_screen", &EndScreenFile, true),
+	stringSetting("secret", &SecretFile, true),
+	stringSetting("token", &TokenFile, true),
+	stringSetting("output_dir", &OutputDir, false),
+	stringSetting("output_template", &OutputTemplate, false),
+	oneOfSetting("on_collision", &OnCollision, "number", "overwrite", "fail"),
+	intSetting("concurrency", &Concurrency),
+	intSetting("requests", &Requests),
+	stringSetting("season", &Season, false),
+	oneOfSetting("league", &League, "nba", "wnba", "gleague"),
+	oneOfSetting("privacy_status", &PrivacyStatus, "public", "unlisted", "private"),
+}
+
+func lookup(key string) (*setting, bool) {
+	for _, s := range settings {
+		if s.key == key {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// executable is os.Executable, swapped out by tests to put legacy files
+// somewhere they control.
+var executable = os.Executable
+
+// DefaultFile is where the config file is looked for when neither --config
+// nor BASKETBALL_CONFIG says otherwise, e.g. ~/.config/basketball/config.yaml.
+func DefaultFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "basketball", "config.yaml"), nil
+}
+
+// dataDir is $XDG_DATA_HOME/basketball, or ~/.local/share/basketball.
+func dataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "basketball"), nil
+	}
+	home, err := os.UserHomeDir()
 	if err != nil {
-		panic(err)
+		return "", err
 	}
+	return filepath.Join(home, ".local", "share", "basketball"), nil
+}
 
-	DatabaseFile = filepath.Join(filepath.Dir(dir), "database.db")
-	EndScreenFile = filepath.Join(filepath.Dir(dir), "end.mp4")
-	SecretFile = filepath.Join(filepath.Dir(dir), "secret.json")
-	TokenFile = filepath.Join(filepath.Dir(dir), "token.json")
+// LoadConfig works out every setting. Later sources win: defaults, files next
+// to the executable, the config file, BASKETBALL_* environment variables and
+// finally overrides, which come from --set key=value flags. file is the
+// config file from --config, if it was given.
+func LoadConfig(file string, overrides []string) error {
+	exe, err := executable()
+	if err != nil {
+		return err
+	}
+	exeDir := filepath.Dir(exe)
+	data, err := dataDir()
+	if err != nil {
+		return err
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	defaultFile, err := DefaultFile()
+	if err != nil {
+		return err
+	}
+	configDir := filepath.Dir(defaultFile)
+
+	DatabaseFile = filepath.Join(data, "database.db")
+	EndScreenFile = filepath.Join(data, "end.mp4")
+	SecretFile = filepath.Join(configDir, "secret.json")
+	TokenFile = filepath.Join(configDir, "token.json")
+	OutputDir = filepath.Join(home, "Downloads")
+	OutputTemplate = "{{.Season}}/{{.Team}}/{{.Player}}_{{.Date}}_{{.Recipe}}.mp4"
+	OnCollision = "number"
+	Concurrency = 4
+	Requests = 50
+	Season = ""
+	League = "nba"
+	PrivacyStatus = "public"
+	for _, s := range settings {
+		s.source = "default"
+		if !s.file {
+			continue
+		}
+		legacy := filepath.Join(exeDir, filepath.Base(s.value()))
+		if _, err := os.Stat(s.value()); err == nil {
+			continue
+		}
+		if _, err := os.Stat(legacy); err == nil {
+			s.set(legacy)
+			s.source = "next to executable"
+		}
+	}
+
+	File = ""
+	required := true
+	if file == "" {
+		file = os.Getenv("BASKETBALL_CONFIG")
+	}
+	if file == "" {
+		file, required = defaultFile, false
+	}
+	values := map[string]string{}
+	b, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		// no config file is fine, the defaults are meant to work
+	} else if err != nil {
+		return err
+	} else {
+		if err := yaml.Unmarshal(b, &values); err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+		File = file
+	}
+	for key, v := range values {
+		s, ok := lookup(key)
+		if !ok {
+			return fmt.Errorf("%s: unknown setting %q", file, key)
+		}
+		if err := s.set(expand(v)); err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+		s.source = file
+	}
+
+	for _, s := range settings {
+		if v, ok := os.LookupEnv(s.env()); ok {
+			if err := s.set(expand(v)); err != nil {
+				return fmt.Errorf("%s: %v", s.env(), err)
+			}
+			s.source = "$" + s.env()
+		}
+	}
+
+	for _, o := range overrides {
+		key, v, ok := strings.Cut(o, "=")
+		if !ok {
+			return fmt.Errorf("--set %q: expected key=value", o)
+		}
+		s, ok := lookup(key)
+		if !ok {
+			return fmt.Errorf("--set: unknown setting %q", key)
+		}
+		if err := s.set(expand(v)); err != nil {
+			return fmt.Errorf("--set: %v", err)
+		}
+		s.source = "--set"
+	}
+	return nil
+}
+
+// Set overrides a setting after LoadConfig, for commands with their own flag
+// for it. source is what config show reports, e.g. the flag's name.
+func Set(key, value, source string) error {
+	s, ok := lookup(key)
+	if !ok {
+		return fmt.Errorf("unknown setting %q", key)
+	}
+	if err := s.set(value); err != nil {
+		return err
+	}
+	s.source = source
+	return nil
+}
+
+// expand lets paths start with ~ and use environment variables.
+func expand(v string) string {
+	if v == "~" || strings.HasPrefix(v, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			v = home + v[1:]
+		}
+	}
+	return os.ExpandEnv(v)
+}
+
+// Settings returns every setting's current value and where it came from.
+func Settings() []Setting {
+	out := make([]Setting, len(settings))
+	for i, s := range settings {
+		out[i] = Setting{Key: s.key, Env: s.env(), Value: s.value(), Source: s.source}
+	}
+	return out
 }
@@ -0,0 +1,290 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testEnv points every directory LoadConfig looks in at a fresh temp dir and
+// clears the BASKETBALL_* variables. It returns the temp dir's home, the
+// directory standing in for the executable's and the default config file.
+func testEnv(t *testing.T) (home, exeDir, configFile string) {
+	t.Helper()
+	root := t.TempDir()
+	home = filepath.Join(root, "home")
+	exeDir = filepath.Join(root, "bin")
+	for _, dir := range []string{home, exeDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, ".local", "share"))
+	t.Setenv("BASKETBALL_CONFIG", "")
+	os.Unsetenv("BASKETBALL_CONFIG")
+	for _, s := range settings {
+		t.Setenv(s.env(), "")
+		os.Unsetenv(s.env())
+	}
+	executable = func() (string, error) { return filepath.Join(exeDir, "basketball"), nil }
+	t.Cleanup(func() { executable = os.Executable })
+	return home, exeDir, filepath.Join(home, ".config", "basketball", "config.yaml")
+}
+
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func settingOf(t *testing.T, key string) Setting {
+	t.Helper()
+	for _, s := range Settings() {
+		if s.Key == key {
+			return s
+		}
+	}
+	t.Fatalf("no setting %q", key)
+	return Setting{}
+}
+
+func TestLoadConfigDefaults(t *testing.T) {
+	home, _, _ := testEnv(t)
+	if err := LoadConfig("", nil); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		key, want string
+	}{
+		{"database", filepath.Join(home, ".local", "share", "basketball", "database.db")},
+		{"token", filepath.Join(home, ".config", "basketball", "token.json")},
+		{"output_dir", filepath.Join(home, "Downloads")},
+		{"on_collision", "number"},
+		{"concurrency", "4"},
+		{"requests", "50"},
+		{"season", ""},
+		{"league", "nba"},
+		{"privacy_status", "public"},
+	}
+	for _, tt := range tests {
+		if s := settingOf(t, tt.key); s.Value != tt.want || s.Source != "default" {
+			t.Errorf("%s = %q from %s, want %q from default", tt.key, s.Value, s.Source, tt.want)
+		}
+	}
+	if File != "" {
+		t.Errorf("read config file %q, none exists", File)
+	}
+}
+
+// TestLoadConfigPrecedence layers each source on top of the ones before it
+// and checks the latest one wins.
+func TestLoadConfigPrecedence(t *testing.T) {
+	tests := []struct {
+		name       string
+		legacy     bool
+		file       string
+		env        map[string]string
+		overrides  []string
+		database   string // relative to the temp root's home or bin dir
+		output     string
+		concurrent string
+		source     string
+	}{
+		{
+			name:       "defaults",
+			database:   "home/.local/share/basketball/database.db",
+			output:     "home/Downloads",
+			concurrent: "4",
+			source:     "default",
+		},
+		{
+			name:       "legacy files",
+			legacy:     true,
+			database:   "bin/database.db",
+			output:     "home/Downloads",
+			concurrent: "4",
+			source:     "default",
+		},
+		{
+			name:       "config file",
+			legacy:     true,
+			file:       "database: ~/file.db\noutput_dir: ~/file-reels\nconcurrency: 2\n",
+			database:   "home/file.db",
+			output:     "home/file-reels",
+			concurrent: "2",
+			source:     "file",
+		},
+		{
+			name:       "environment",
+			legacy:     true,
+			file:       "database: ~/file.db\noutput_dir: ~/file-reels\nconcurrency: 2\n",
+			env:        map[string]string{"BASKETBALL_DATABASE": "~/env.db", "BASKETBALL_CONCURRENCY": "3"},
+			database:   "home/env.db",
+			output:     "home/file-reels",
+			concurrent: "3",
+			source:     "$BASKETBALL_CONCURRENCY",
+		},
+		{
+			name:       "--set",
+			legacy:     true,
+			file:       "database: ~/file.db\noutput_dir: ~/file-reels\nconcurrency: 2\n",
+			env:        map[string]string{"BASKETBALL_DATABASE": "~/env.db", "BASKETBALL_CONCURRENCY": "3"},
+			overrides:  []string{"concurrency=8", "output_dir=$HOME/set-reels"},
+			database:   "home/env.db",
+			output:     "home/set-reels",
+			concurrent: "8",
+			source:     "--set",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home, exeDir, configFile := testEnv(t)
+			root := filepath.Dir(home)
+			if tt.legacy {
+				writeFile(t, filepath.Join(exeDir, "database.db"), "")
+			}
+			if tt.file != "" {
+				writeFile(t, configFile, tt.file)
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			if err := LoadConfig("", tt.overrides); err != nil {
+				t.Fatal(err)
+			}
+
+			if want := filepath.Join(root, tt.database); DatabaseFile != want {
+				t.Errorf("database = %q, want %q", DatabaseFile, want)
+			}
+			if want := filepath.Join(root, tt.output); OutputDir != want {
+				t.Errorf("output_dir = %q, want %q", OutputDir, want)
+			}
+			s := settingOf(t, "concurrency")
+			if s.Value != tt.concurrent {
+				t.Errorf("concurrency = %q, want %q", s.Value, tt.concurrent)
+			}
+			source := tt.source
+			if source == "file" {
+				source = configFile
+			}
+			if s.Source != source {
+				t.Errorf("concurrency came from %q, want %q", s.Source, source)
+			}
+		})
+	}
+}
+
+func TestLoadConfigLegacyOnlyWithoutNewDefault(t *testing.T) {
+	home, exeDir, _ := testEnv(t)
+	writeFile(t, filepath.Join(exeDir, "database.db"), "")
+	writeFile(t, filepath.Join(exeDir, "token.json"), "{}")
+	// a database already at the new default wins over the legacy one
+	newDatabase := filepath.Join(home, ".local", "share", "basketball", "database.db")
+	writeFile(t, newDatabase, "")
+	if err := LoadConfig("", nil); err != nil {
+		t.Fatal(err)
+	}
+	if s := settingOf(t, "database"); s.Value != newDatabase || s.Source != "default" {
+		t.Errorf("database = %q from %s, want %q from default", s.Value, s.Source, newDatabase)
+	}
+	if s := settingOf(t, "token"); s.Value != filepath.Join(exeDir, "token.json") || s.Source != "next to executable" {
+		t.Errorf("token = %q from %s, want the legacy token", s.Value, s.Source)
+	}
+}
+
+func TestLoadConfigFileLocation(t *testing.T) {
+	home, _, defaultFile := testEnv(t)
+	writeFile(t, defaultFile, "season: 2023-24\n")
+	flagFile := filepath.Join(home, "flag.yaml")
+	writeFile(t, flagFile, "season: 2022-23\n")
+	envFile := filepath.Join(home, "env.yaml")
+	writeFile(t, envFile, "season: 2021-22\n")
+
+	tests := []struct {
+		name, flag, env string
+		want, wantFile  string
+	}{
+		{"default file", "", "", "2023-24", defaultFile},
+		{"$BASKETBALL_CONFIG", "", envFile, "2021-22", envFile},
+		{"--config beats $BASKETBALL_CONFIG", flagFile, envFile, "2022-23", flagFile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("BASKETBALL_CONFIG", tt.env)
+			}
+			if err := LoadConfig(tt.flag, nil); err != nil {
+				t.Fatal(err)
+			}
+			if Season != tt.want || File != tt.wantFile {
+				t.Errorf("season %q from %q, want %q from %q", Season, File, tt.want, tt.wantFile)
+			}
+		})
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		flag      string
+		file      string
+		env       map[string]string
+		overrides []string
+		wantErr   string
+	}{
+		{name: "missing --config", flag: "missing.yaml", wantErr: "missing.yaml"},
+		{name: "missing $BASKETBALL_CONFIG", env: map[string]string{"BASKETBALL_CONFIG": "/nowhere/config.yaml"}, wantErr: "/nowhere/config.yaml"},
+		{name: "unknown setting in file", file: "colour: blue\n", wantErr: `unknown setting "colour"`},
+		{name: "bad yaml", file: "concurrency: [1\n", wantErr: "config.yaml"},
+		{name: "bad value in file", file: "concurrency: many\n", wantErr: "concurrency must be a positive number"},
+		{name: "bad value in env", env: map[string]string{"BASKETBALL_ON_COLLISION": "shrug"}, wantErr: "BASKETBALL_ON_COLLISION: on_collision must be one of"},
+		{name: "zero in --set", overrides: []string{"requests=0"}, wantErr: "requests must be a positive number"},
+		{name: "--set without =", overrides: []string{"league"}, wantErr: "expected key=value"},
+		{name: "unknown --set", overrides: []string{"colour=blue"}, wantErr: `unknown setting "colour"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home, _, configFile := testEnv(t)
+			if tt.file != "" {
+				writeFile(t, configFile, tt.file)
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			flag := tt.flag
+			if flag != "" {
+				flag = filepath.Join(home, flag)
+			}
+			err := LoadConfig(flag, tt.overrides)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSet(t *testing.T) {
+	testEnv(t)
+	if err := LoadConfig("", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set("on_collision", "fail", "--on-collision"); err != nil {
+		t.Fatal(err)
+	}
+	if s := settingOf(t, "on_collision"); s.Value != "fail" || s.Source != "--on-collision" {
+		t.Errorf("got %+v", s)
+	}
+	if err := Set("on_collision", "shrug", "--on-collision"); err == nil {
+		t.Error("expected an error for a value that isn't allowed")
+	}
+	if err := Set("colour", "blue", "--colour"); err == nil {
+		t.Error("expected an error for an unknown setting")
+	}
+}
@@ -0,0 +1,218 @@
+package db
+
+import (
+	"basketball/nba"
+	"basketball/utils"
+
+	"fmt"
+	"log"
+	"strings"
+)
+
+func (s *Store) InsertClips(assets []nba.VideoDetailAsset) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", utils.ErrorWithTrace(err))
+	}
+
+	stmt, err := tx.Prepare(
+		`INSERT OR REPLACE INTO clips (
+			uuid,
+			game_id,
+			event_id,
+			player_id,
+			team_id,
+			context_measure,
+			period,
+			home_abbreviation,
+			visiting_abbreviation,
+			home_points_before,
+			home_points_after,
+			visiting_points_before,
+			visiting_points_after,
+			description,
+			small_dur,
+			small_url,
+			med_dur,
+			med_url,
+			large_dur,
+			large_url
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+	)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("error preparing statement: %v", utils.ErrorWithTrace(err))
+	}
+	defer stmt.Close()
+
+	for _, a := range assets {
+		if a.Uuid == nil || a.GameID == nil || a.PlayerID == nil || a.TeamID == nil {
+			log.Printf("skipping clip with missing uuid, game, player or team: %v", a.Description)
+			continue
+		}
+		_, err := stmt.Exec(
+			*a.Uuid,
+			*a.GameID,
+			a.EventID,
+			*a.PlayerID,
+			*a.TeamID,
+			string(a.ContextMeasure),
+			a.Period,
+			a.HomeAbbreviation,
+			a.VisitingAbbreviation,
+			a.HomePointsBefore,
+			a.HomePointsAfter,
+			a.VisitingPointsBefore,
+			a.VisitingPointsAfter,
+			a.Description,
+			a.SmallDur,
+			a.SmallUrl,
+			a.MedDur,
+			a.MedUrl,
+			a.LargeDur,
+			a.LargeUrl,
+		)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("error inserting clip %s: %v", *a.Uuid, utils.ErrorWithTrace(err))
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %v", utils.ErrorWithTrace(err))
+	}
+	return nil
+}
+
+const clipColumns = `clips.uuid,
+			clips.game_id,
+			clips.event_id,
+			clips.player_id,
+			clips.team_id,
+			clips.context_measure,
+			clips.period,
+			clips.home_abbreviation,
+			clips.visiting_abbreviation,
+			clips.home_points_before,
+			clips.home_points_after,
+			clips.visiting_points_before,
+			clips.visiting_points_after,
+			clips.description,
+			clips.small_dur,
+			clips.small_url,
+			clips.med_dur,
+			clips.med_url,
+			clips.large_dur,
+			clips.large_url`
+
+func scanClip(row scanner) (nba.VideoDetailAsset, error) {
+	var c nba.VideoDetailAsset
+	var measure string
+	err := row.Scan(
+		&c.Uuid,
+		&c.GameID,
+		&c.EventID,
+		&c.PlayerID,
+		&c.TeamID,
+		&measure,
+		&c.Period,
+		&c.HomeAbbreviation,
+		&c.VisitingAbbreviation,
+		&c.HomePointsBefore,
+		&c.HomePointsAfter,
+		&c.VisitingPointsBefore,
+		&c.VisitingPointsAfter,
+		&c.Description,
+		&c.SmallDur,
+		&c.SmallUrl,
+		&c.MedDur,
+		&c.MedUrl,
+		&c.LargeDur,
+		&c.LargeUrl,
+	)
+	c.ContextMeasure = nba.VideoDetailsAssetContextMeasure(measure)
+	return c, err
+}
+
+func (s *Store) queryClips(query string, args ...any) ([]nba.VideoDetailAsset, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, utils.ErrorWithTrace(err)
+	}
+	defer rows.Close()
+
+	clips := []nba.VideoDetailAsset{}
+	for rows.Next() {
+		c, err := scanClip(rows)
+		if err != nil {
+			return nil, utils.ErrorWithTrace(err)
+		}
+		clips = append(clips, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, utils.ErrorWithTrace(err)
+	}
+	return clips, nil
+}
+
+// Clips returns the cataloged clips for a player's game, filtered to the given
+// context measures (all measures if none are given), ordered by event.
+func (s *Store) Clips(gameID string, playerID int, measures ...nba.VideoDetailsAssetContextMeasure) ([]nba.VideoDetailAsset, error) {
+	query := "SELECT " + clipColumns + " FROM clips WHERE game_id = ? AND player_id = ?"
+	args := []any{gameID, playerID}
+	if len(measures) > 0 {
+		query += " AND context_measure IN (?" + strings.Repeat(", ?", len(measures)-1) + ")"
+		for _, m := range measures {
+			args = append(args, string(m))
+		}
+	}
+	query += " ORDER BY period, event_id"
+	return s.queryClips(query, args...)
+}
+
+// SetJobClips records which clips the job's reel is cut from, in reel order.
+// The catalog keeps growing as clips are fetched again or published late, so
+// the job has to remember its own.
+func (s *Store) SetJobClips(jobID int64, assets []nba.VideoDetailAsset) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", utils.ErrorWithTrace(err))
+	}
+	if _, err := tx.Exec("DELETE FROM job_clips WHERE job_id = ?", jobID); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("error clearing clips of job %d: %v", jobID, utils.ErrorWithTrace(err))
+	}
+	for i, a := range assets {
+		if a.Uuid == nil {
+			tx.Rollback()
+			return fmt.Errorf("clip with no uuid can't be kept for job %d: %v", jobID, a.Description)
+		}
+		_, err := tx.Exec(
+			"INSERT INTO job_clips (job_id, position, uuid, context_measure) VALUES (?, ?, ?, ?)",
+			jobID, i, *a.Uuid, string(a.ContextMeasure),
+		)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("error recording clip %s of job %d: %v", *a.Uuid, jobID, utils.ErrorWithTrace(err))
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %v", utils.ErrorWithTrace(err))
+	}
+	return nil
+}
+
+// JobClips returns the clips recorded for the job by SetJobClips, in reel
+// order, as they are now in the catalog.
+func (s *Store) JobClips(jobID int64) ([]nba.VideoDetailAsset, error) {
+	return s.queryClips(
+		"SELECT "+clipColumns+` FROM job_clips
+		JOIN jobs ON jobs.id = job_clips.job_id
+		JOIN clips ON clips.uuid = job_clips.uuid
+			AND clips.game_id = jobs.game_id
+			AND clips.player_id = jobs.player_id
+			AND clips.context_measure = job_clips.context_measure
+		WHERE job_clips.job_id = ?
+		ORDER BY job_clips.position`,
+		jobID,
+	)
+}
@@ -1,152 +1,158 @@
 package db
 
 import (
-	"basketball/config"
-	"basketball/nba"
 	"basketball/utils"
 
 	"database/sql"
-	_ "embed"
+	"embed"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 
 	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	_ "github.com/mattn/go-sqlite3"
 )
 
-func SetupDatabase() {
-	if _, err := os.Stat(config.DatabaseFile); os.IsNotExist(err) {
-		log.Println("Database file not found. Creating a new database.")
-		file, err := os.Create(config.DatabaseFile)
-		if err != nil {
-			log.Fatalf("Failed to create database file: %v", utils.ErrorWithTrace(err))
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+//go:embed asciitball.txt
+var chunkyDunker string
+
+// MemoryDatabase can be passed to Open for a throwaway in-memory database.
+const MemoryDatabase = ":memory:"
+
+// Store owns the single database handle shared by the whole program.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens (creating if necessary) the sqlite database at path with WAL
+// journaling, a busy timeout and foreign key enforcement turned on.
+func Open(path string) (*Store, error) {
+	if path != MemoryDatabase {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			log.Println("Database file not found. Creating a new database.")
 		}
-		file.Close()
 	}
-}
 
-func RunMigrations() {
-	m, err := migrate.New(
-		"file://db/migrations",
-		"sqlite3://"+config.DatabaseFile,
-	)
+	db, err := sql.Open("sqlite3", path+"?_journal_mode=WAL&_busy_timeout=5000&_foreign_keys=on")
 	if err != nil {
-		log.Fatalf("Failed to initialize migrations: %v", err)
+		return nil, fmt.Errorf("failed to open database: %v", utils.ErrorWithTrace(err))
+	}
+	if path == MemoryDatabase {
+		// every connection to :memory: is a brand new database
+		db.SetMaxOpenConns(1)
 	}
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		log.Fatalf("Failed to apply migrations: %v", err)
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open database: %v", utils.ErrorWithTrace(err))
 	}
-	log.Println("Migrations applied successfully.")
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
 }
 
-func ValidateMigrations() error {
-	db, err := sql.Open("sqlite3", config.DatabaseFile)
+func (s *Store) newMigrate() (*migrate.Migrate, error) {
+	source, err := iofs.New(migrations, "migrations")
 	if err != nil {
-		return fmt.Errorf("failed to open databse: %v", utils.ErrorWithTrace(err))
+		return nil, utils.ErrorWithTrace(err)
 	}
-	defer db.Close()
-
-	var count int
-	err = db.QueryRow("Select COUNT(*) FROM teams").Scan(&count)
+	driver, err := sqlite3.WithInstance(s.db, &sqlite3.Config{})
 	if err != nil {
-		panic(fmt.Errorf("failed to query teams table: %v", utils.ErrorWithTrace(err)))
+		return nil, utils.ErrorWithTrace(err)
 	}
-
-	if count != 31 {
-		panic(fmt.Errorf("expected 31 teams, found %d", count))
+	m, err := migrate.NewWithInstance("iofs", source, "sqlite3", driver)
+	if err != nil {
+		return nil, utils.ErrorWithTrace(err)
 	}
+	// m.Close() is never called because it would close the shared handle
+	return m, nil
+}
 
-	var name string
-	err = db.QueryRow("SELECT name FROM teams WHERE id = 1610612752").Scan(&name)
+// MigrateUp applies every embedded migration that has not been applied yet.
+func (s *Store) MigrateUp() error {
+	m, err := s.newMigrate()
 	if err != nil {
-		panic(fmt.Errorf("failed to find Knicks: %v", utils.ErrorWithTrace(err)))
+		return err
 	}
-	if name != "New York Knicks" {
-		panic(fmt.Errorf("expected team.id 1610612752 to have name 'New York Knicks', got '%s'", name))
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to apply migrations: %v", err)
+	}
+	return nil
+}
+
+// MigrateDown rolls back the given number of applied migrations.
+func (s *Store) MigrateDown(steps int) error {
+	if steps < 1 {
+		return fmt.Errorf("expected a positive number of steps, got %d", steps)
 	}
-	err = db.QueryRow("SELECT name FROM teams WHERE id = 0").Scan(&name)
+	m, err := s.newMigrate()
 	if err != nil {
-		panic(fmt.Errorf("failed to find NULL_TEAM: %v", utils.ErrorWithTrace(err)))
+		return err
 	}
-	if name != "NULL_TEAM" {
-		panic(fmt.Errorf("expected team.id 0 to have name 'NULL_TEAM', got '%s'", name))
+	if err := m.Steps(-steps); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
 	}
-	log.Printf("Database validation successful: found %d teams\n", count)
 	return nil
 }
 
-//go:embed asciitball.txt
-var chunkyDunker string
-
-func PlayerIDFromCode(playerCode string) (int, error) {
-	db, err := sql.Open("sqlite3", config.DatabaseFile)
+// MigrationVersion reports the currently applied migration version and
+// whether the last migration failed part way through. A database with no
+// migrations applied reports version 0.
+func (s *Store) MigrationVersion() (uint, bool, error) {
+	m, err := s.newMigrate()
 	if err != nil {
-		return -1, fmt.Errorf("failed to open databse: %v", utils.ErrorWithTrace(err))
+		return 0, false, err
 	}
-	defer db.Close()
+	version, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
 
-	var id int
-	err = db.QueryRow("SELECT id FROM players WHERE name = $1", playerCode).Scan(&id)
+// ForceMigration sets the migration version without running any migrations
+// and clears the dirty flag. It is used to recover from a failed migration.
+func (s *Store) ForceMigration(version int) error {
+	m, err := s.newMigrate()
 	if err != nil {
-		fmt.Println(playerCode)
-		return -1, utils.ErrorWithTrace(err)
+		return err
 	}
-	return id, nil
+	return m.Force(version)
 }
 
-func InsertPlayers(players []nba.CommonAllPlayer) {
-	db, err := sql.Open("sqlite3", config.DatabaseFile)
+func (s *Store) ValidateMigrations() error {
+	var count int
+	err := s.db.QueryRow("Select COUNT(*) FROM teams WHERE league_id = '00'").Scan(&count)
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("failed to query teams table: %v", utils.ErrorWithTrace(err))
 	}
-	defer db.Close()
 
-	tx, err := db.Begin()
-	if err != nil {
-		log.Printf("Error beginning transaction: %v", utils.ErrorWithTrace(err))
+	if count != 31 {
+		return fmt.Errorf("expected 31 NBA teams, found %d", count)
 	}
 
-	stmt, err := tx.Prepare(
-		`INSERT OR REPLACE INTO players (
-			id,
-			name,
-			team_id
-			) VALUES (?, ?, ?)`,
-	)
+	var name string
+	err = s.db.QueryRow("SELECT name FROM teams WHERE id = 1610612752").Scan(&name)
 	if err != nil {
-		tx.Rollback()
-		log.Printf("Error preparing statement: %v", utils.ErrorWithTrace(err))
+		return fmt.Errorf("failed to find Knicks: %v", utils.ErrorWithTrace(err))
 	}
-	defer stmt.Close()
-
-	for _, player := range players {
-		if player.PersonID == nil {
-			log.Printf("found player with nil PersonID: %s", *player.DisplayFirstLast)
-			continue
-		}
-		if player.DisplayFirstLast == nil {
-			log.Printf("found player with nil DisplayFirstLast: %d", int(*player.PersonID))
-			continue
-		}
-		if player.TeamID == nil {
-			log.Printf("found player with nil TeamID: %s", *player.DisplayFirstLast)
-			continue
-		}
-		_, err := stmt.Exec(
-			*player.PersonID,
-			*player.DisplayFirstLast,
-			*player.TeamID,
-		)
-		if err != nil {
-			tx.Rollback()
-			log.Printf("error inserting player %s(%d): %v\n", *player.DisplayFirstLast, int(*player.PersonID), err)
-			return
-		}
+	if name != "New York Knicks" {
+		return fmt.Errorf("expected team.id 1610612752 to have name 'New York Knicks', got '%s'", name)
 	}
-	err = tx.Commit()
+	err = s.db.QueryRow("SELECT name FROM teams WHERE id = 0").Scan(&name)
 	if err != nil {
-		log.Printf("Error committing transaction: %v", utils.ErrorWithTrace(err))
+		return fmt.Errorf("failed to find NULL_TEAM: %v", utils.ErrorWithTrace(err))
+	}
+	if name != "NULL_TEAM" {
+		return fmt.Errorf("expected team.id 0 to have name 'NULL_TEAM', got '%s'", name)
 	}
+	log.Printf("Database validation successful: found %d teams\n", count)
+	return nil
 }
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"io/fs"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// openTestStore opens a fresh in-memory database with every migration
+// applied.
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(MemoryDatabase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	if err := s.MigrateUp(); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func latestMigration(t *testing.T) uint {
+	t.Helper()
+	names, err := fs.Glob(migrations, "migrations/*.up.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest := uint64(0)
+	for _, name := range names {
+		version, _, _ := strings.Cut(strings.TrimPrefix(name, "migrations/"), "_")
+		v, err := strconv.ParseUint(version, 10, 64)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		latest = max(latest, v)
+	}
+	return uint(latest)
+}
+
+func TestMigrateUp(t *testing.T) {
+	s := openTestStore(t)
+	version, dirty, err := s.MigrationVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := latestMigration(t); version != want || dirty {
+		t.Errorf("got version %d (dirty %v), want %d", version, dirty, want)
+	}
+	if err := s.ValidateMigrations(); err != nil {
+		t.Error(err)
+	}
+	// running them again is a no-op
+	if err := s.MigrateUp(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMigrateDownAndUp(t *testing.T) {
+	s := openTestStore(t)
+	latest := latestMigration(t)
+	if err := s.MigrateDown(1); err != nil {
+		t.Fatal(err)
+	}
+	if version, _, err := s.MigrationVersion(); err != nil || version >= latest {
+		t.Fatalf("got version %d, %v after rolling back one, want below %d", version, err, latest)
+	}
+	if err := s.MigrateUp(); err != nil {
+		t.Fatal(err)
+	}
+	if version, _, err := s.MigrationVersion(); err != nil || version != latest {
+		t.Errorf("got version %d, %v after migrating up again, want %d", version, err, latest)
+	}
+}
@@ -0,0 +1,131 @@
+package db
+
+import (
+	"basketball/nba"
+	"basketball/utils"
+
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type Game struct {
+	ID         string
+	SeasonID   string
+	Date       string
+	HomeTeamID int
+	AwayTeamID int
+}
+
+// UpsertGames records the games found by the league game finder. Each finder
+// row only tells us about one side of the game, so a row fills in the home or
+// away team without clearing what an earlier row recorded for the other side.
+// That side's team is added first when we don't have it yet, e.g. a G League
+// opponent that was never synced, and a team the row doesn't name is left
+// out rather than breaking the foreign key.
+func (s *Store) UpsertGames(games []nba.LeagueGameFinderGame) error {
+	teams := []Team{}
+	for _, g := range games {
+		if g.GameID == nil || g.TeamID == nil || g.TeamName == nil {
+			continue
+		}
+		team := Team{ID: int(*g.TeamID), LeagueID: nba.LeagueFromGameID(*g.GameID), Name: *g.TeamName}
+		if g.TeamAbbreviation != nil {
+			team.Abbreviation = *g.TeamAbbreviation
+		}
+		teams = append(teams, team)
+	}
+	if err := s.UpsertTeams(teams); err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", utils.ErrorWithTrace(err))
+	}
+
+	stmt, err := tx.Prepare(
+		`INSERT INTO games (
+			id,
+			season_id,
+			game_date,
+			home_team_id,
+			away_team_id
+			) VALUES (?, ?, ?, (SELECT id FROM teams WHERE id = ?), (SELECT id FROM teams WHERE id = ?))
+		ON CONFLICT (id) DO UPDATE SET
+			season_id = COALESCE(excluded.season_id, season_id),
+			game_date = COALESCE(excluded.game_date, game_date),
+			home_team_id = COALESCE(excluded.home_team_id, home_team_id),
+			away_team_id = COALESCE(excluded.away_team_id, away_team_id)`,
+	)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("error preparing statement: %v", utils.ErrorWithTrace(err))
+	}
+	defer stmt.Close()
+
+	for _, g := range games {
+		if g.GameID == nil {
+			continue
+		}
+		var home, away *float64
+		if g.Matchup != nil && strings.Contains(*g.Matchup, " vs. ") {
+			home = g.TeamID
+		} else if g.Matchup != nil && strings.Contains(*g.Matchup, " @ ") {
+			away = g.TeamID
+		}
+		if _, err := stmt.Exec(*g.GameID, g.SeasonID, g.GameDate, home, away); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("error inserting game %s: %v", *g.GameID, utils.ErrorWithTrace(err))
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %v", utils.ErrorWithTrace(err))
+	}
+	return nil
+}
+
+const gameColumns = `id,
+			COALESCE(season_id, ''),
+			COALESCE(game_date, ''),
+			COALESCE(home_team_id, 0),
+			COALESCE(away_team_id, 0)`
+
+func scanGame(row scanner) (Game, error) {
+	g := Game{}
+	err := row.Scan(&g.ID, &g.SeasonID, &g.Date, &g.HomeTeamID, &g.AwayTeamID)
+	return g, err
+}
+
+func (s *Store) Game(id string) (Game, error) {
+	g, err := scanGame(s.db.QueryRow("SELECT "+gameColumns+" FROM games WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return g, fmt.Errorf("no game with id %s", id)
+	} else if err != nil {
+		return g, utils.ErrorWithTrace(err)
+	}
+	return g, nil
+}
+
+// GamesByTeam returns the recorded games a team played in, most recent first.
+func (s *Store) GamesByTeam(teamID int) ([]Game, error) {
+	rows, err := s.db.Query("SELECT "+gameColumns+" FROM games WHERE home_team_id = ? OR away_team_id = ? ORDER BY game_date DESC", teamID, teamID)
+	if err != nil {
+		return nil, utils.ErrorWithTrace(err)
+	}
+	defer rows.Close()
+
+	games := []Game{}
+	for rows.Next() {
+		g, err := scanGame(rows)
+		if err != nil {
+			return nil, utils.ErrorWithTrace(err)
+		}
+		games = append(games, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, utils.ErrorWithTrace(err)
+	}
+	return games, nil
+}
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"basketball/nba"
+
+	"testing"
+)
+
+func TestUpsertGames(t *testing.T) {
+	s := openTestStore(t)
+	knicks := nba.LeagueGameFinderGame{
+		SeasonID: ptr("22024"),
+		TeamID:   ptr(1610612752.0),
+		GameID:   ptr("0022400001"),
+		GameDate: ptr("2024-10-22"),
+		Matchup:  ptr("NYK @ BOS"),
+	}
+	celtics := nba.LeagueGameFinderGame{
+		SeasonID: ptr("22024"),
+		TeamID:   ptr(1610612738.0),
+		GameID:   ptr("0022400001"),
+		GameDate: ptr("2024-10-22"),
+		Matchup:  ptr("BOS vs. NYK"),
+	}
+
+	// each side's row fills in its own team without clearing the other's
+	if err := s.UpsertGames([]nba.LeagueGameFinderGame{knicks}); err != nil {
+		t.Fatal(err)
+	}
+	g, err := s.Game("0022400001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.AwayTeamID != 1610612752 || g.HomeTeamID != 0 {
+		t.Errorf("after the Knicks' row got %+v", g)
+	}
+	if err := s.UpsertGames([]nba.LeagueGameFinderGame{celtics, {}}); err != nil {
+		t.Fatal(err)
+	}
+	want := Game{ID: "0022400001", SeasonID: "22024", Date: "2024-10-22", HomeTeamID: 1610612738, AwayTeamID: 1610612752}
+	if g, err = s.Game("0022400001"); err != nil {
+		t.Fatal(err)
+	}
+	if g != want {
+		t.Errorf("got %+v, want %+v", g, want)
+	}
+
+	for _, teamID := range []int{1610612752, 1610612738} {
+		games, err := s.GamesByTeam(teamID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(games) != 1 || games[0] != want {
+			t.Errorf("GamesByTeam(%d) = %+v", teamID, games)
+		}
+	}
+	if _, err := s.Game("0022400002"); err == nil {
+		t.Error("expected an error for a game that was never recorded")
+	}
+}
+
+func TestUpsertGamesAddsTeams(t *testing.T) {
+	s := openTestStore(t)
+	games := []nba.LeagueGameFinderGame{
+		{
+			SeasonID:         ptr("22024"),
+			TeamID:           ptr(1612709890.0),
+			TeamAbbreviation: ptr("WES"),
+			TeamName:         ptr("Westchester Knicks"),
+			GameID:           ptr("2022400101"),
+			GameDate:         ptr("2024-11-08"),
+			Matchup:          ptr("WES vs. MNE"),
+		},
+		// a row that doesn't name its team can't add it
+		{
+			SeasonID: ptr("22024"),
+			TeamID:   ptr(1612709911.0),
+			GameID:   ptr("2022400101"),
+			GameDate: ptr("2024-11-08"),
+			Matchup:  ptr("MNE @ WES"),
+		},
+	}
+	if err := s.UpsertGames(games); err != nil {
+		t.Fatal(err)
+	}
+	g, err := s.Game("2022400101")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.HomeTeamID != 1612709890 || g.AwayTeamID != 0 {
+		t.Errorf("got %+v", g)
+	}
+	team, err := s.Team(1612709890)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if team.Name != "Westchester Knicks" || team.Abbreviation != "WES" || team.LeagueID != nba.LeagueGLeague {
+		t.Errorf("got team %+v", team)
+	}
+}
@@ -0,0 +1,166 @@
+package db
+
+import (
+	"basketball/nba"
+	"basketball/utils"
+
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// JobStage is the next stage a job has to run. A job only moves to the next
+// stage once the previous one has completed, so a crashed or failed job can
+// pick up where it left off.
+type JobStage string
+
+const (
+	JobStageFetch    JobStage = "fetch"
+	JobStageDownload JobStage = "download"
+	JobStageRender   JobStage = "render"
+	JobStageUpload   JobStage = "upload"
+	JobStageDone     JobStage = "done"
+)
+
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "pending"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusFailed    JobStatus = "failed"
+	JobStatusDone      JobStatus = "done"
+	JobStatusCancelled JobStatus = "cancelled"
+)
+
+// Job is one player's reel moving through the highlight pipeline. Game is the
+// game finder row the reel is built from, kept so a resumed job doesn't have
+// to query for it again.
+type Job struct {
+	ID         int64
+	PlayerID   int
+	PlayerName string
+	GameID     string
+	Recipe     string
+	Game       nba.LeagueGameFinderGame
+	Stage      JobStage
+	Status     JobStatus
+	ClipCount  int
+	TmpDir     string
+	OutputFile string
+	Error      string
+	CreatedAt  string
+	UpdatedAt  string
+}
+
+const jobColumns = `id,
+			player_id,
+			player_name,
+			game_id,
+			recipe,
+			game,
+			stage,
+			status,
+			clip_count,
+			COALESCE(tmp_dir, ''),
+			COALESCE(output_file, ''),
+			COALESCE(error, ''),
+			created_at,
+			updated_at`
+
+func scanJob(row scanner) (Job, error) {
+	j := Job{}
+	var game string
+	err := row.Scan(&j.ID, &j.PlayerID, &j.PlayerName, &j.GameID, &j.Recipe, &game, &j.Stage, &j.Status, &j.ClipCount, &j.TmpDir, &j.OutputFile, &j.Error, &j.CreatedAt, &j.UpdatedAt)
+	if err != nil {
+		return j, err
+	}
+	if err := json.Unmarshal([]byte(game), &j.Game); err != nil {
+		return j, fmt.Errorf("job %d has an unreadable game: %v", j.ID, err)
+	}
+	return j, nil
+}
+
+// EnsureJob queues a new job for the player, game and recipe unless one
+// already exists, and returns whichever job is now in the queue.
+func (s *Store) EnsureJob(j Job) (Job, error) {
+	game, err := json.Marshal(j.Game)
+	if err != nil {
+		return j, utils.ErrorWithTrace(err)
+	}
+	_, err = s.db.Exec(
+		`INSERT OR IGNORE INTO jobs (
+			player_id,
+			player_name,
+			game_id,
+			recipe,
+			game,
+			stage,
+			status
+			) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		j.PlayerID, j.PlayerName, j.GameID, j.Recipe, string(game), string(JobStageFetch), string(JobStatusPending),
+	)
+	if err != nil {
+		return j, fmt.Errorf("error queueing job: %v", utils.ErrorWithTrace(err))
+	}
+	job, err := scanJob(s.db.QueryRow(
+		"SELECT "+jobColumns+" FROM jobs WHERE player_id = ? AND game_id = ? AND recipe = ?",
+		j.PlayerID, j.GameID, j.Recipe,
+	))
+	if err != nil {
+		return j, utils.ErrorWithTrace(err)
+	}
+	return job, nil
+}
+
+func (s *Store) Job(id int64) (Job, error) {
+	j, err := scanJob(s.db.QueryRow("SELECT "+jobColumns+" FROM jobs WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return j, fmt.Errorf("no job with id %d", id)
+	} else if err != nil {
+		return j, utils.ErrorWithTrace(err)
+	}
+	return j, nil
+}
+
+// Jobs returns every job, most recently created first.
+func (s *Store) Jobs() ([]Job, error) {
+	rows, err := s.db.Query("SELECT " + jobColumns + " FROM jobs ORDER BY id DESC")
+	if err != nil {
+		return nil, utils.ErrorWithTrace(err)
+	}
+	defer rows.Close()
+
+	jobs := []Job{}
+	for rows.Next() {
+		j, err := scanJob(rows)
+		if err != nil {
+			return nil, utils.ErrorWithTrace(err)
+		}
+		jobs = append(jobs, j)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, utils.ErrorWithTrace(err)
+	}
+	return jobs, nil
+}
+
+// UpdateJob persists the job's stage, status and working files.
+func (s *Store) UpdateJob(j Job) error {
+	_, err := s.db.Exec(
+		`UPDATE jobs SET
+			stage = ?,
+			status = ?,
+			clip_count = ?,
+			tmp_dir = ?,
+			output_file = ?,
+			error = ?,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`,
+		string(j.Stage), string(j.Status), j.ClipCount, j.TmpDir, j.OutputFile, j.Error, j.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("error updating job %d: %v", j.ID, utils.ErrorWithTrace(err))
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package db
+
+import (
+	"basketball/nba"
+
+	"testing"
+)
+
+func TestEnsureJob(t *testing.T) {
+	s := openTestStore(t)
+	game := nba.LeagueGameFinderGame{
+		PlayerId:   ptr(1628973.0),
+		PlayerName: ptr("Jalen Brunson"),
+		GameID:     ptr("0022400001"),
+		PTS:        ptr(22.0),
+	}
+	job, err := s.EnsureJob(Job{PlayerID: 1628973, PlayerName: "Jalen Brunson", GameID: "0022400001", Recipe: "highlights", Game: game})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.ID == 0 || job.Stage != JobStageFetch || job.Status != JobStatusPending {
+		t.Fatalf("new job is %+v", job)
+	}
+	if job.Game.PTS == nil || *job.Game.PTS != 22 {
+		t.Errorf("game didn't round-trip: %+v", job.Game)
+	}
+
+	job.Stage = JobStageUpload
+	job.Status = JobStatusPending
+	job.ClipCount = 12
+	job.TmpDir = "/tmp/brunson"
+	job.OutputFile = "/reels/brunson.mp4"
+	job.Error = "flaky"
+	if err := s.UpdateJob(job); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.Job(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Stage != job.Stage || got.Status != job.Status || got.ClipCount != 12 || got.TmpDir != job.TmpDir || got.OutputFile != job.OutputFile || got.Error != job.Error {
+		t.Errorf("got %+v after updating to %+v", got, job)
+	}
+
+	// queueing the same reel again returns the job as it is, not a new one
+	again, err := s.EnsureJob(Job{PlayerID: 1628973, PlayerName: "Jalen Brunson", GameID: "0022400001", Recipe: "highlights", Game: game})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != job.ID || again.Stage != JobStageUpload || again.OutputFile != job.OutputFile {
+		t.Errorf("got %+v, want the existing job %+v", again, got)
+	}
+
+	other, err := s.EnsureJob(Job{PlayerID: 1628973, PlayerName: "Jalen Brunson", GameID: "0022400002", Recipe: "highlights", Game: game})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.ID == job.ID {
+		t.Error("a different game got the same job")
+	}
+	jobs, err := s.Jobs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2 || jobs[0].ID != other.ID {
+		t.Errorf("got %+v, want both jobs, newest first", jobs)
+	}
+	if _, err := s.Job(other.ID + 1); err == nil {
+		t.Error("expected an error for a job that doesn't exist")
+	}
+}
+
+func TestJobClips(t *testing.T) {
+	s := openTestStore(t)
+	clip := func(uuid string, event float64, measure nba.VideoDetailsAssetContextMeasure) nba.VideoDetailAsset {
+		return nba.VideoDetailAsset{
+			Uuid:           ptr(uuid),
+			GameID:         ptr("0022400001"),
+			EventID:        ptr(event),
+			PlayerID:       ptr(1628973.0),
+			TeamID:         ptr(1610612752.0),
+			ContextMeasure: measure,
+			Period:         ptr(1.0),
+			LargeUrl:       ptr("https://videos.nba.com/" + uuid + ".mp4"),
+		}
+	}
+	fetched := []nba.VideoDetailAsset{
+		clip("c", 30, nba.VideoDetailsAssetContextMeasures.AST),
+		clip("a", 10, nba.VideoDetailsAssetContextMeasures.FGA),
+		clip("b", 20, nba.VideoDetailsAssetContextMeasures.FGA),
+	}
+	if err := s.InsertClips(fetched); err != nil {
+		t.Fatal(err)
+	}
+	job, err := s.EnsureJob(Job{PlayerID: 1628973, PlayerName: "Jalen Brunson", GameID: "0022400001", Recipe: "highlights"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetJobClips(job.ID, fetched); err != nil {
+		t.Fatal(err)
+	}
+
+	// clips cataloged after the fetch, and a measure the clip also counts
+	// for, stay out of the job
+	late := []nba.VideoDetailAsset{
+		clip("d", 40, nba.VideoDetailsAssetContextMeasures.FGA),
+		clip("a", 10, nba.VideoDetailsAssetContextMeasures.REB),
+	}
+	if err := s.InsertClips(late); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.JobClips(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"c", "a", "b"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d clips, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if *got[i].Uuid != want[i] || got[i].ContextMeasure != fetched[i].ContextMeasure {
+			t.Errorf("clip %d is %s %s, want %s %s", i, *got[i].Uuid, got[i].ContextMeasure, want[i], fetched[i].ContextMeasure)
+		}
+	}
+
+	// fetching again replaces the job's clips
+	if err := s.SetJobClips(job.ID, late[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = s.JobClips(job.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || *got[0].Uuid != "d" {
+		t.Errorf("got %d clips after fetching again, want just d", len(got))
+	}
+	if err := s.SetJobClips(job.ID, []nba.VideoDetailAsset{{}}); err == nil {
+		t.Error("expected an error for a clip with no uuid")
+	}
+}
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS clips_game_event;
+
+DROP INDEX IF EXISTS clips_context_measure;
+
+DROP INDEX IF EXISTS clips_player_game;
+
+DROP INDEX IF EXISTS clips_game_id;
+
+DROP TABLE IF EXISTS clips;
@@ -0,0 +1,33 @@
+CREATE TABLE
+  IF NOT EXISTS clips (
+    uuid TEXT NOT NULL,
+    game_id TEXT NOT NULL,
+    event_id INT,
+    player_id INT NOT NULL,
+    team_id INT NOT NULL,
+    context_measure TEXT NOT NULL,
+    period INT,
+    home_abbreviation TEXT,
+    visiting_abbreviation TEXT,
+    home_points_before INT,
+    home_points_after INT,
+    visiting_points_before INT,
+    visiting_points_after INT,
+    description TEXT,
+    small_dur REAL,
+    small_url TEXT,
+    med_dur REAL,
+    med_url TEXT,
+    large_dur REAL,
+    large_url TEXT,
+    fetched_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (uuid, player_id, context_measure)
+  );
+
+CREATE INDEX IF NOT EXISTS clips_game_id ON clips (game_id);
+
+CREATE INDEX IF NOT EXISTS clips_player_game ON clips (player_id, game_id);
+
+CREATE INDEX IF NOT EXISTS clips_context_measure ON clips (context_measure);
+
+CREATE INDEX IF NOT EXISTS clips_game_event ON clips (game_id, event_id);
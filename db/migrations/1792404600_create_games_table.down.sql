@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS games_game_date;
+
+DROP TABLE IF EXISTS games;
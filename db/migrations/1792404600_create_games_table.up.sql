@@ -0,0 +1,12 @@
+CREATE TABLE
+  IF NOT EXISTS games (
+    id TEXT PRIMARY KEY UNIQUE,
+    season_id TEXT,
+    game_date TEXT,
+    home_team_id INT,
+    away_team_id INT,
+    FOREIGN KEY (home_team_id) REFERENCES teams (id),
+    FOREIGN KEY (away_team_id) REFERENCES teams (id)
+  );
+
+CREATE INDEX IF NOT EXISTS games_game_date ON games (game_date);
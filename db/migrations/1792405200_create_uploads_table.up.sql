@@ -0,0 +1,13 @@
+CREATE TABLE
+  IF NOT EXISTS uploads (
+    player_id INT NOT NULL,
+    game_id TEXT NOT NULL,
+    recipe TEXT NOT NULL,
+    video_id TEXT,
+    title TEXT,
+    description_hash TEXT,
+    status TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (player_id, game_id, recipe)
+  );
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS jobs_status;
+
+DROP TABLE IF EXISTS jobs;
@@ -0,0 +1,20 @@
+CREATE TABLE
+  IF NOT EXISTS jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    player_id INT NOT NULL,
+    player_name TEXT NOT NULL,
+    game_id TEXT NOT NULL,
+    recipe TEXT NOT NULL,
+    game TEXT NOT NULL,
+    stage TEXT NOT NULL,
+    status TEXT NOT NULL,
+    clip_count INT NOT NULL DEFAULT 0,
+    tmp_dir TEXT,
+    output_file TEXT,
+    error TEXT,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (player_id, game_id, recipe)
+  );
+
+CREATE INDEX IF NOT EXISTS jobs_status ON jobs (status);
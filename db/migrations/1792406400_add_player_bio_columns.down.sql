@@ -0,0 +1,13 @@
+ALTER TABLE players DROP COLUMN bio_updated_at;
+ALTER TABLE players DROP COLUMN draft_number;
+ALTER TABLE players DROP COLUMN draft_round;
+ALTER TABLE players DROP COLUMN draft_year;
+ALTER TABLE players DROP COLUMN country;
+ALTER TABLE players DROP COLUMN school;
+ALTER TABLE players DROP COLUMN birthdate;
+ALTER TABLE players DROP COLUMN weight;
+ALTER TABLE players DROP COLUMN height;
+ALTER TABLE players DROP COLUMN position;
+ALTER TABLE players DROP COLUMN jersey;
+ALTER TABLE players DROP COLUMN last_name;
+ALTER TABLE players DROP COLUMN first_name;
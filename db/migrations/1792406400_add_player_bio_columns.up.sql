@@ -0,0 +1,13 @@
+ALTER TABLE players ADD COLUMN first_name TEXT;
+ALTER TABLE players ADD COLUMN last_name TEXT;
+ALTER TABLE players ADD COLUMN jersey TEXT;
+ALTER TABLE players ADD COLUMN position TEXT;
+ALTER TABLE players ADD COLUMN height TEXT;
+ALTER TABLE players ADD COLUMN weight TEXT;
+ALTER TABLE players ADD COLUMN birthdate TEXT;
+ALTER TABLE players ADD COLUMN school TEXT;
+ALTER TABLE players ADD COLUMN country TEXT;
+ALTER TABLE players ADD COLUMN draft_year TEXT;
+ALTER TABLE players ADD COLUMN draft_round TEXT;
+ALTER TABLE players ADD COLUMN draft_number TEXT;
+ALTER TABLE players ADD COLUMN bio_updated_at TEXT;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS players_league_id;
+DROP INDEX IF EXISTS teams_league_id;
+ALTER TABLE players DROP COLUMN league_id;
+ALTER TABLE teams DROP COLUMN league_id;
@@ -0,0 +1,6 @@
+ALTER TABLE teams ADD COLUMN league_id TEXT NOT NULL DEFAULT '00';
+ALTER TABLE players ADD COLUMN league_id TEXT NOT NULL DEFAULT '00';
+
+CREATE INDEX IF NOT EXISTS teams_league_id ON teams (league_id);
+
+CREATE INDEX IF NOT EXISTS players_league_id ON players (league_id);
@@ -0,0 +1,30 @@
+UPDATE teams SET city = 'Atlanta', abbreviation = 'ATL', conference = 'East', division = 'Southeast' WHERE id = 1610612737;
+UPDATE teams SET city = 'Boston', abbreviation = 'BOS', conference = 'East', division = 'Atlantic' WHERE id = 1610612738;
+UPDATE teams SET city = 'Brooklyn', abbreviation = 'BKN', conference = 'East', division = 'Atlantic' WHERE id = 1610612751;
+UPDATE teams SET city = 'Charlotte', abbreviation = 'CHA', conference = 'East', division = 'Southeast' WHERE id = 1610612766;
+UPDATE teams SET city = 'Chicago', abbreviation = 'CHI', conference = 'East', division = 'Central' WHERE id = 1610612741;
+UPDATE teams SET city = 'Cleveland', abbreviation = 'CLE', conference = 'East', division = 'Central' WHERE id = 1610612739;
+UPDATE teams SET city = 'Dallas', abbreviation = 'DAL', conference = 'West', division = 'Southwest' WHERE id = 1610612742;
+UPDATE teams SET city = 'Denver', abbreviation = 'DEN', conference = 'West', division = 'Northwest' WHERE id = 1610612743;
+UPDATE teams SET city = 'Detroit', abbreviation = 'DET', conference = 'East', division = 'Central' WHERE id = 1610612765;
+UPDATE teams SET city = 'Golden State', abbreviation = 'GSW', conference = 'West', division = 'Pacific' WHERE id = 1610612744;
+UPDATE teams SET city = 'Houston', abbreviation = 'HOU', conference = 'West', division = 'Southwest' WHERE id = 1610612745;
+UPDATE teams SET city = 'Indiana', abbreviation = 'IND', conference = 'East', division = 'Central' WHERE id = 1610612754;
+UPDATE teams SET city = 'Los Angeles', abbreviation = 'LAC', conference = 'West', division = 'Pacific' WHERE id = 1610612746;
+UPDATE teams SET city = 'Los Angeles', abbreviation = 'LAL', conference = 'West', division = 'Pacific' WHERE id = 1610612747;
+UPDATE teams SET city = 'Memphis', abbreviation = 'MEM', conference = 'West', division = 'Southwest' WHERE id = 1610612763;
+UPDATE teams SET city = 'Miami', abbreviation = 'MIA', conference = 'East', division = 'Southeast' WHERE id = 1610612748;
+UPDATE teams SET city = 'Milwaukee', abbreviation = 'MIL', conference = 'East', division = 'Central' WHERE id = 1610612749;
+UPDATE teams SET city = 'Minnesota', abbreviation = 'MIN', conference = 'West', division = 'Northwest' WHERE id = 1610612750;
+UPDATE teams SET city = 'New Orleans', abbreviation = 'NOP', conference = 'West', division = 'Southwest' WHERE id = 1610612740;
+UPDATE teams SET city = 'New York', abbreviation = 'NYK', conference = 'East', division = 'Atlantic' WHERE id = 1610612752;
+UPDATE teams SET city = 'Oklahoma City', abbreviation = 'OKC', conference = 'West', division = 'Northwest' WHERE id = 1610612760;
+UPDATE teams SET city = 'Orlando', abbreviation = 'ORL', conference = 'East', division = 'Southeast' WHERE id = 1610612753;
+UPDATE teams SET city = 'Philadelphia', abbreviation = 'PHI', conference = 'East', division = 'Atlantic' WHERE id = 1610612755;
+UPDATE teams SET city = 'Phoenix', abbreviation = 'PHX', conference = 'West', division = 'Pacific' WHERE id = 1610612756;
+UPDATE teams SET city = 'Portland', abbreviation = 'POR', conference = 'West', division = 'Northwest' WHERE id = 1610612757;
+UPDATE teams SET city = 'Sacramento', abbreviation = 'SAC', conference = 'West', division = 'Pacific' WHERE id = 1610612758;
+UPDATE teams SET city = 'San Antonio', abbreviation = 'SAS', conference = 'West', division = 'Southwest' WHERE id = 1610612759;
+UPDATE teams SET city = 'Toronto', abbreviation = 'TOR', conference = 'East', division = 'Atlantic' WHERE id = 1610612761;
+UPDATE teams SET city = 'Utah', abbreviation = 'UTA', conference = 'West', division = 'Northwest' WHERE id = 1610612762;
+UPDATE teams SET city = 'Washington', abbreviation = 'WAS', conference = 'East', division = 'Southeast' WHERE id = 1610612764;
@@ -0,0 +1,8 @@
+CREATE TABLE
+  IF NOT EXISTS job_clips (
+    job_id INTEGER NOT NULL REFERENCES jobs (id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    uuid TEXT NOT NULL,
+    context_measure TEXT NOT NULL,
+    PRIMARY KEY (job_id, position)
+  );
@@ -0,0 +1,9 @@
+CREATE TABLE
+  IF NOT EXISTS player_leagues (
+    player_id INT NOT NULL REFERENCES players (id) ON DELETE CASCADE,
+    league_id TEXT NOT NULL,
+    PRIMARY KEY (player_id, league_id)
+  );
+
+INSERT OR IGNORE INTO player_leagues (player_id, league_id)
+SELECT id, league_id FROM players;
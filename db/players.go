@@ -0,0 +1,375 @@
+package db
+
+import (
+	"basketball/nba"
+	"basketball/utils"
+
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+)
+
+type Player struct {
+	ID int
+	// LeagueID is the league the player was first stored in. Players on
+	// two-way contracts are in more than one, see player_leagues.
+	LeagueID    nba.League
+	Name        string
+	TeamID      int
+	FirstName   string
+	LastName    string
+	Jersey      string
+	Position    string
+	Height      string
+	Weight      string
+	Birthdate   string
+	School      string
+	Country     string
+	DraftYear   string
+	DraftRound  string
+	DraftNumber string
+}
+
+const playerColumns = `id,
+			league_id,
+			COALESCE(name, ''),
+			COALESCE(team_id, 0),
+			COALESCE(first_name, ''),
+			COALESCE(last_name, ''),
+			COALESCE(jersey, ''),
+			COALESCE(position, ''),
+			COALESCE(height, ''),
+			COALESCE(weight, ''),
+			COALESCE(birthdate, ''),
+			COALESCE(school, ''),
+			COALESCE(country, ''),
+			COALESCE(draft_year, ''),
+			COALESCE(draft_round, ''),
+			COALESCE(draft_number, '')`
+
+func scanPlayer(row scanner) (Player, error) {
+	p := Player{}
+	err := row.Scan(
+		&p.ID,
+		&p.LeagueID,
+		&p.Name,
+		&p.TeamID,
+		&p.FirstName,
+		&p.LastName,
+		&p.Jersey,
+		&p.Position,
+		&p.Height,
+		&p.Weight,
+		&p.Birthdate,
+		&p.School,
+		&p.Country,
+		&p.DraftYear,
+		&p.DraftRound,
+		&p.DraftNumber,
+	)
+	return p, err
+}
+
+func (s *Store) PlayerIDFromCode(league nba.League, playerCode string) (int, error) {
+	var id int
+	err := s.db.QueryRow(
+		`SELECT id FROM players
+		JOIN player_leagues ON player_leagues.player_id = players.id
+		WHERE name = $1 AND player_leagues.league_id = $2`,
+		playerCode, league,
+	).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return -1, fmt.Errorf("no %s player named %q", league, playerCode)
+	} else if err != nil {
+		return -1, utils.ErrorWithTrace(err)
+	}
+	return id, nil
+}
+
+func (s *Store) Player(id int) (Player, error) {
+	p, err := scanPlayer(s.db.QueryRow("SELECT "+playerColumns+" FROM players WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return p, fmt.Errorf("no player with id %d", id)
+	} else if err != nil {
+		return p, utils.ErrorWithTrace(err)
+	}
+	return p, nil
+}
+
+// addPlayerLeague records that a player plays in a league. A player keeps
+// every league they've been synced in, so a G League call-up is still found
+// in the G League after an NBA sync.
+const addPlayerLeague = "INSERT OR IGNORE INTO player_leagues (player_id, league_id) VALUES (?, ?)"
+
+func (s *Store) PlayersByTeam(teamID int) ([]Player, error) {
+	rows, err := s.db.Query("SELECT "+playerColumns+" FROM players WHERE team_id = ? ORDER BY name", teamID)
+	if err != nil {
+		return nil, utils.ErrorWithTrace(err)
+	}
+	defer rows.Close()
+
+	players := []Player{}
+	for rows.Next() {
+		p, err := scanPlayer(rows)
+		if err != nil {
+			return nil, utils.ErrorWithTrace(err)
+		}
+		players = append(players, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, utils.ErrorWithTrace(err)
+	}
+	return players, nil
+}
+
+// InsertPlayers stores a league's players. Teams the players are on are added
+// first since the seeded teams table only has NBA teams.
+func (s *Store) InsertPlayers(league nba.League, players []nba.CommonAllPlayer) error {
+	teams := []Team{}
+	for _, p := range players {
+		if p.TeamID == nil || p.TeamCity == nil || p.TeamName == nil || p.TeamAbbreviation == nil {
+			continue
+		}
+		teams = append(teams, Team{
+			ID:           int(*p.TeamID),
+			LeagueID:     league,
+			Name:         *p.TeamCity + " " + *p.TeamName,
+			City:         *p.TeamCity,
+			Abbreviation: *p.TeamAbbreviation,
+		})
+	}
+	if err := s.UpsertTeams(teams); err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", utils.ErrorWithTrace(err))
+	}
+
+	stmt, err := tx.Prepare(
+		`INSERT INTO players (
+			id,
+			league_id,
+			name,
+			team_id
+			) VALUES (?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			name = excluded.name,
+			team_id = excluded.team_id`,
+	)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("error preparing statement: %v", utils.ErrorWithTrace(err))
+	}
+	defer stmt.Close()
+	leagueStmt, err := tx.Prepare(addPlayerLeague)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("error preparing statement: %v", utils.ErrorWithTrace(err))
+	}
+	defer leagueStmt.Close()
+
+	for _, player := range players {
+		if player.PersonID == nil {
+			log.Printf("found player with nil PersonID: %v", player.DisplayFirstLast)
+			continue
+		}
+		if player.DisplayFirstLast == nil {
+			log.Printf("found player with nil DisplayFirstLast: %d", int(*player.PersonID))
+			continue
+		}
+		if player.TeamID == nil {
+			log.Printf("found player with nil TeamID: %s", *player.DisplayFirstLast)
+			continue
+		}
+		_, err := stmt.Exec(
+			*player.PersonID,
+			league,
+			*player.DisplayFirstLast,
+			*player.TeamID,
+		)
+		if err == nil {
+			_, err = leagueStmt.Exec(*player.PersonID, league)
+		}
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("error inserting player %s(%d): %v", *player.DisplayFirstLast, int(*player.PersonID), err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %v", utils.ErrorWithTrace(err))
+	}
+	return nil
+}
+
+// UpsertPlayerInfo stores a player's bio, adding the player if they aren't in
+// the players table yet. The player's team is added too when the info names
+// it, since only NBA teams are seeded; a team it doesn't name and we don't
+// have is left for the next sync to fill in.
+func (s *Store) UpsertPlayerInfo(league nba.League, info nba.CommonPlayerInfoData) error {
+	if info.PersonID == nil || info.DisplayFirstLast == nil {
+		return fmt.Errorf("player info is missing an id or name")
+	}
+	teamID := info.TeamID
+	if teamID != nil && info.TeamCity != nil && info.TeamName != nil {
+		team := Team{
+			ID:       int(*teamID),
+			LeagueID: league,
+			Name:     *info.TeamCity + " " + *info.TeamName,
+			City:     *info.TeamCity,
+		}
+		if info.TeamAbbreviation != nil {
+			team.Abbreviation = *info.TeamAbbreviation
+		}
+		if err := s.UpsertTeams([]Team{team}); err != nil {
+			return err
+		}
+	} else if teamID != nil {
+		var known bool
+		if err := s.db.QueryRow("SELECT EXISTS (SELECT 1 FROM teams WHERE id = ?)", *teamID).Scan(&known); err != nil {
+			return utils.ErrorWithTrace(err)
+		}
+		if !known {
+			teamID = nil
+		}
+	}
+	_, err := s.db.Exec(
+		`INSERT INTO players (
+			id,
+			league_id,
+			name,
+			team_id,
+			first_name,
+			last_name,
+			jersey,
+			position,
+			height,
+			weight,
+			birthdate,
+			school,
+			country,
+			draft_year,
+			draft_round,
+			draft_number,
+			bio_updated_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (id) DO UPDATE SET
+			name = excluded.name,
+			team_id = COALESCE(excluded.team_id, team_id),
+			first_name = excluded.first_name,
+			last_name = excluded.last_name,
+			jersey = excluded.jersey,
+			position = excluded.position,
+			height = excluded.height,
+			weight = excluded.weight,
+			birthdate = excluded.birthdate,
+			school = excluded.school,
+			country = excluded.country,
+			draft_year = excluded.draft_year,
+			draft_round = excluded.draft_round,
+			draft_number = excluded.draft_number,
+			bio_updated_at = excluded.bio_updated_at`,
+		*info.PersonID,
+		league,
+		*info.DisplayFirstLast,
+		teamID,
+		info.FirstName,
+		info.LastName,
+		info.Jersey,
+		info.Position,
+		info.Height,
+		info.Weight,
+		info.Birthdate,
+		info.School,
+		info.Country,
+		info.DraftYear,
+		info.DraftRound,
+		info.DraftNumber,
+	)
+	if err == nil {
+		_, err = s.db.Exec(addPlayerLeague, *info.PersonID, league)
+	}
+	if err != nil {
+		return fmt.Errorf("error storing player info for %s(%d): %v", *info.DisplayFirstLast, int(*info.PersonID), utils.ErrorWithTrace(err))
+	}
+	return nil
+}
+
+// UpsertRoster moves every player on the roster to the roster's team and
+// stores the bio details the roster carries. Draft details are left alone
+// since only commonplayerinfo has them.
+func (s *Store) UpsertRoster(league nba.League, roster nba.CommonTeamRosterData) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", utils.ErrorWithTrace(err))
+	}
+
+	stmt, err := tx.Prepare(
+		`INSERT INTO players (
+			id,
+			league_id,
+			name,
+			team_id,
+			jersey,
+			position,
+			height,
+			weight,
+			birthdate,
+			school,
+			bio_updated_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (id) DO UPDATE SET
+			name = excluded.name,
+			team_id = excluded.team_id,
+			jersey = excluded.jersey,
+			position = excluded.position,
+			height = excluded.height,
+			weight = excluded.weight,
+			birthdate = COALESCE(birthdate, excluded.birthdate),
+			school = excluded.school,
+			bio_updated_at = excluded.bio_updated_at`,
+	)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("error preparing statement: %v", utils.ErrorWithTrace(err))
+	}
+	defer stmt.Close()
+	leagueStmt, err := tx.Prepare(addPlayerLeague)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("error preparing statement: %v", utils.ErrorWithTrace(err))
+	}
+	defer leagueStmt.Close()
+
+	for _, p := range roster.Players {
+		if p.PlayerID == nil || p.Player == nil || p.TeamID == nil {
+			log.Printf("skipping roster entry with missing id, name or team: %v", p.Player)
+			continue
+		}
+		_, err := stmt.Exec(
+			*p.PlayerID,
+			league,
+			*p.Player,
+			*p.TeamID,
+			p.Num,
+			p.Position,
+			p.Height,
+			p.Weight,
+			p.BirthDate,
+			p.School,
+		)
+		if err == nil {
+			_, err = leagueStmt.Exec(*p.PlayerID, league)
+		}
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("error storing roster player %s(%d): %v", *p.Player, int(*p.PlayerID), utils.ErrorWithTrace(err))
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %v", utils.ErrorWithTrace(err))
+	}
+	return nil
+}
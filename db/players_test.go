@@ -0,0 +1,232 @@
+package db
+
+import (
+	"basketball/nba"
+
+	"testing"
+)
+
+func TestInsertPlayers(t *testing.T) {
+	s := openTestStore(t)
+	brunson := nba.CommonAllPlayer{
+		PersonID:         ptr(1628973.0),
+		DisplayFirstLast: ptr("Jalen Brunson"),
+		TeamID:           ptr(1610612752.0),
+	}
+	hart := nba.CommonAllPlayer{
+		PersonID:         ptr(1628404.0),
+		DisplayFirstLast: ptr("Josh Hart"),
+		TeamID:           ptr(1610612752.0),
+	}
+	// a player listed twice and a sync run twice both leave one row each
+	players := []nba.CommonAllPlayer{brunson, hart, brunson}
+	for range 2 {
+		if err := s.InsertPlayers(nba.LeagueNBA, players); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	roster, err := s.PlayersByTeam(1610612752)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roster) != 2 {
+		t.Fatalf("got %d players, want 2: %+v", len(roster), roster)
+	}
+
+	tests := []struct {
+		league  nba.League
+		code    string
+		want    int
+		wantErr bool
+	}{
+		{nba.LeagueNBA, "Jalen Brunson", 1628973, false},
+		{nba.LeagueNBA, "Josh Hart", 1628404, false},
+		{nba.LeagueNBA, "jalen brunson", -1, true},
+		{nba.LeagueNBA, "Caitlin Clark", -1, true},
+		{nba.LeagueWNBA, "Jalen Brunson", -1, true},
+	}
+	for _, tt := range tests {
+		got, err := s.PlayerIDFromCode(tt.league, tt.code)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("PlayerIDFromCode(%s, %q) = %d, %v, want %d (error %v)", tt.league, tt.code, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestInsertPlayersAddsTheirTeams(t *testing.T) {
+	s := openTestStore(t)
+	players := []nba.CommonAllPlayer{{
+		PersonID:         ptr(1642286.0),
+		DisplayFirstLast: ptr("Caitlin Clark"),
+		TeamID:           ptr(1611661325.0),
+		TeamCity:         ptr("Indiana"),
+		TeamName:         ptr("Fever"),
+		TeamAbbreviation: ptr("IND"),
+	}}
+	if err := s.InsertPlayers(nba.LeagueWNBA, players); err != nil {
+		t.Fatal(err)
+	}
+	team, err := s.Team(1611661325)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if team.Name != "Indiana Fever" || team.LeagueID != nba.LeagueWNBA {
+		t.Errorf("got team %+v", team)
+	}
+	if id, err := s.PlayerIDFromCode(nba.LeagueWNBA, "Caitlin Clark"); err != nil || id != 1642286 {
+		t.Errorf("got %d, %v", id, err)
+	}
+}
+
+func TestUpsertPlayerInfoTeams(t *testing.T) {
+	tests := []struct {
+		name     string
+		league   nba.League
+		info     nba.CommonPlayerInfoData
+		wantTeam int
+	}{
+		{
+			name:   "seeded team",
+			league: nba.LeagueNBA,
+			info: nba.CommonPlayerInfoData{
+				PersonID:         ptr(1628973.0),
+				DisplayFirstLast: ptr("Jalen Brunson"),
+				TeamID:           ptr(1610612752.0),
+			},
+			wantTeam: 1610612752,
+		},
+		{
+			name:   "team the info names is added",
+			league: nba.LeagueWNBA,
+			info: nba.CommonPlayerInfoData{
+				PersonID:         ptr(1642286.0),
+				DisplayFirstLast: ptr("Caitlin Clark"),
+				TeamID:           ptr(1611661325.0),
+				TeamCity:         ptr("Indiana"),
+				TeamName:         ptr("Fever"),
+				TeamAbbreviation: ptr("IND"),
+			},
+			wantTeam: 1611661325,
+		},
+		{
+			name:   "unknown team is left empty",
+			league: nba.LeagueGLeague,
+			info: nba.CommonPlayerInfoData{
+				PersonID:         ptr(1641000.0),
+				DisplayFirstLast: ptr("G League Player"),
+				TeamID:           ptr(1612709890.0),
+				Height:           ptr("6-5"),
+			},
+			wantTeam: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := openTestStore(t)
+			if err := s.UpsertPlayerInfo(tt.league, tt.info); err != nil {
+				t.Fatal(err)
+			}
+			p, err := s.Player(int(*tt.info.PersonID))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.TeamID != tt.wantTeam || p.LeagueID != tt.league {
+				t.Errorf("got team %d in %s, want %d in %s", p.TeamID, p.LeagueID, tt.wantTeam, tt.league)
+			}
+			if tt.wantTeam != 0 {
+				if _, err := s.Team(tt.wantTeam); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+}
+
+func TestUpsertPlayerInfoKeepsTeamUntilSync(t *testing.T) {
+	s := openTestStore(t)
+	info := nba.CommonPlayerInfoData{
+		PersonID:         ptr(1641000.0),
+		DisplayFirstLast: ptr("G League Player"),
+		TeamID:           ptr(1612709890.0),
+	}
+	if err := s.UpsertPlayerInfo(nba.LeagueGLeague, info); err != nil {
+		t.Fatal(err)
+	}
+	// a sync that knows the team fills it in, and a later bio without one
+	// doesn't clear it
+	players := []nba.CommonAllPlayer{{
+		PersonID:         ptr(1641000.0),
+		DisplayFirstLast: ptr("G League Player"),
+		TeamID:           ptr(1612709890.0),
+		TeamCity:         ptr("Westchester"),
+		TeamName:         ptr("Knicks"),
+		TeamAbbreviation: ptr("WES"),
+	}}
+	if err := s.InsertPlayers(nba.LeagueGLeague, players); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpsertPlayerInfo(nba.LeagueGLeague, nba.CommonPlayerInfoData{PersonID: info.PersonID, DisplayFirstLast: info.DisplayFirstLast}); err != nil {
+		t.Fatal(err)
+	}
+	p, err := s.Player(1641000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.TeamID != 1612709890 {
+		t.Errorf("got team %d, want 1612709890", p.TeamID)
+	}
+}
+
+// TestTwoWayPlayer checks a player synced in two leagues is found in both,
+// whichever sync ran last.
+func TestTwoWayPlayer(t *testing.T) {
+	s := openTestStore(t)
+	player := nba.CommonAllPlayer{
+		PersonID:         ptr(1641000.0),
+		DisplayFirstLast: ptr("Two Way Player"),
+		TeamID:           ptr(1612709890.0),
+		TeamCity:         ptr("Westchester"),
+		TeamName:         ptr("Knicks"),
+		TeamAbbreviation: ptr("WES"),
+	}
+	if err := s.InsertPlayers(nba.LeagueGLeague, []nba.CommonAllPlayer{player}); err != nil {
+		t.Fatal(err)
+	}
+	player.TeamID, player.TeamCity, player.TeamName, player.TeamAbbreviation = ptr(1610612752.0), nil, nil, nil
+	if err := s.InsertPlayers(nba.LeagueNBA, []nba.CommonAllPlayer{player}); err != nil {
+		t.Fatal(err)
+	}
+	info := nba.CommonPlayerInfoData{PersonID: player.PersonID, DisplayFirstLast: player.DisplayFirstLast}
+	if err := s.UpsertPlayerInfo(nba.LeagueNBA, info); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, league := range []nba.League{nba.LeagueGLeague, nba.LeagueNBA} {
+		if id, err := s.PlayerIDFromCode(league, "Two Way Player"); err != nil || id != 1641000 {
+			t.Errorf("PlayerIDFromCode(%s) = %d, %v", league, id, err)
+		}
+	}
+	if _, err := s.PlayerIDFromCode(nba.LeagueWNBA, "Two Way Player"); err == nil {
+		t.Error("expected an error in a league the player isn't in")
+	}
+	p, err := s.Player(1641000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.LeagueID != nba.LeagueGLeague || p.TeamID != 1610612752 {
+		t.Errorf("got %+v, want the first league and the latest team", p)
+	}
+
+	// a team keeps its league whatever league it's upserted from
+	if err := s.UpsertTeams([]Team{{ID: 1612709890, LeagueID: nba.LeagueNBA, Name: "Westchester Knicks"}}); err != nil {
+		t.Fatal(err)
+	}
+	team, err := s.Team(1612709890)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if team.LeagueID != nba.LeagueGLeague {
+		t.Errorf("got team %+v", team)
+	}
+}
@@ -0,0 +1,181 @@
+package db
+
+import (
+	"basketball/nba"
+	"basketball/utils"
+
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type Team struct {
+	ID           int
+	LeagueID     nba.League
+	Name         string
+	City         string
+	Abbreviation string
+	Conference   string
+	Division     string
+}
+
+const teamColumns = `id,
+			league_id,
+			name,
+			COALESCE(city, ''),
+			COALESCE(abbreviation, ''),
+			COALESCE(conference, ''),
+			COALESCE(division, '')`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTeam(row scanner) (Team, error) {
+	t := Team{}
+	err := row.Scan(&t.ID, &t.LeagueID, &t.Name, &t.City, &t.Abbreviation, &t.Conference, &t.Division)
+	return t, err
+}
+
+func (s *Store) Team(id int) (Team, error) {
+	t, err := scanTeam(s.db.QueryRow("SELECT "+teamColumns+" FROM teams WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return t, fmt.Errorf("no team with id %d", id)
+	} else if err != nil {
+		return t, utils.ErrorWithTrace(err)
+	}
+	return t, nil
+}
+
+// TeamByAbbreviation finds a league's team by its tricode (NYK, NYL, ...).
+func (s *Store) TeamByAbbreviation(league nba.League, abbreviation string) (Team, error) {
+	t, err := scanTeam(s.db.QueryRow("SELECT "+teamColumns+" FROM teams WHERE league_id = ? AND abbreviation = ? COLLATE NOCASE", league, abbreviation))
+	if errors.Is(err, sql.ErrNoRows) {
+		return t, fmt.Errorf("no %s team with abbreviation %q", league, abbreviation)
+	} else if err != nil {
+		return t, utils.ErrorWithTrace(err)
+	}
+	return t, nil
+}
+
+// FindTeam finds a league's team by id, tricode (NYK), full name (New York
+// Knicks), nickname (Knicks) or city (New York). It's an error when more than
+// one team matches, e.g. "Los Angeles".
+func (s *Store) FindTeam(league nba.League, query string) (Team, error) {
+	query = strings.TrimSpace(query)
+	if id, err := strconv.Atoi(query); err == nil {
+		t, err := s.Team(id)
+		if err == nil && t.LeagueID != league {
+			return t, fmt.Errorf("team %d is a %s team, not %s", id, t.LeagueID, league)
+		}
+		return t, err
+	}
+	if t, err := s.TeamByAbbreviation(league, query); err == nil {
+		return t, nil
+	}
+
+	rows, err := s.db.Query(
+		"SELECT "+teamColumns+` FROM teams
+		WHERE id != 0 AND league_id = ?1 AND (
+			name = ?2 COLLATE NOCASE
+			OR name LIKE '% ' || ?2
+			OR city = ?2 COLLATE NOCASE
+		)
+		ORDER BY name`,
+		league, query,
+	)
+	if err != nil {
+		return Team{}, utils.ErrorWithTrace(err)
+	}
+	defer rows.Close()
+
+	matches := []Team{}
+	for rows.Next() {
+		t, err := scanTeam(rows)
+		if err != nil {
+			return Team{}, utils.ErrorWithTrace(err)
+		}
+		matches = append(matches, t)
+	}
+	if err := rows.Err(); err != nil {
+		return Team{}, utils.ErrorWithTrace(err)
+	}
+
+	switch len(matches) {
+	case 0:
+		return Team{}, fmt.Errorf("no %s team matches %q", league, query)
+	case 1:
+		return matches[0], nil
+	}
+	names := make([]string, len(matches))
+	for i, t := range matches {
+		names[i] = t.Name
+	}
+	return Team{}, fmt.Errorf("%q matches more than one %s team: %s", query, league, strings.Join(names, ", "))
+}
+
+// Teams returns every team in the league except NULL_TEAM, ordered by name.
+func (s *Store) Teams(league nba.League) ([]Team, error) {
+	rows, err := s.db.Query("SELECT "+teamColumns+" FROM teams WHERE id != 0 AND league_id = ? ORDER BY name", league)
+	if err != nil {
+		return nil, utils.ErrorWithTrace(err)
+	}
+	defer rows.Close()
+
+	teams := []Team{}
+	for rows.Next() {
+		t, err := scanTeam(rows)
+		if err != nil {
+			return nil, utils.ErrorWithTrace(err)
+		}
+		teams = append(teams, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, utils.ErrorWithTrace(err)
+	}
+	return teams, nil
+}
+
+// UpsertTeams adds teams we haven't seen before and fills in the city and
+// abbreviation of ones we have. Names are left alone so the seeded NBA names
+// stay as they are, and so are leagues since a team only plays in one.
+func (s *Store) UpsertTeams(teams []Team) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", utils.ErrorWithTrace(err))
+	}
+
+	stmt, err := tx.Prepare(
+		`INSERT INTO teams (
+			id,
+			league_id,
+			name,
+			city,
+			abbreviation
+			) VALUES (?, ?, ?, NULLIF(?, ''), NULLIF(?, ''))
+		ON CONFLICT (id) DO UPDATE SET
+			city = COALESCE(excluded.city, city),
+			abbreviation = COALESCE(excluded.abbreviation, abbreviation)`,
+	)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("error preparing statement: %v", utils.ErrorWithTrace(err))
+	}
+	defer stmt.Close()
+
+	for _, t := range teams {
+		if t.ID == 0 {
+			continue
+		}
+		if _, err := stmt.Exec(t.ID, t.LeagueID, t.Name, t.City, t.Abbreviation); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("error storing team %s(%d): %v", t.Name, t.ID, utils.ErrorWithTrace(err))
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %v", utils.ErrorWithTrace(err))
+	}
+	return nil
+}
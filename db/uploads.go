@@ -0,0 +1,113 @@
+package db
+
+import (
+	"basketball/utils"
+
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// UploadStatus is where a reel's upload got to. An uploading row with a
+// video id is a video that went up but whose thumbnail wasn't set yet.
+type UploadStatus string
+
+const (
+	UploadStatusUploading UploadStatus = "uploading"
+	UploadStatusUploaded  UploadStatus = "uploaded"
+	UploadStatusFailed    UploadStatus = "failed"
+)
+
+// Upload is one row of the upload ledger. A reel is identified by the player,
+// the game and the recipe it was cut with.
+type Upload struct {
+	PlayerID        int
+	GameID          string
+	Recipe          string
+	VideoID         string
+	Title           string
+	DescriptionHash string
+	Status          UploadStatus
+	CreatedAt       string
+	UpdatedAt       string
+}
+
+const uploadColumns = `player_id,
+			game_id,
+			recipe,
+			COALESCE(video_id, ''),
+			COALESCE(title, ''),
+			COALESCE(description_hash, ''),
+			status,
+			created_at,
+			updated_at`
+
+func scanUpload(row scanner) (Upload, error) {
+	u := Upload{}
+	err := row.Scan(&u.PlayerID, &u.GameID, &u.Recipe, &u.VideoID, &u.Title, &u.DescriptionHash, &u.Status, &u.CreatedAt, &u.UpdatedAt)
+	return u, err
+}
+
+// Upload looks up the ledger entry for a reel. The bool reports whether one
+// was found.
+func (s *Store) Upload(playerID int, gameID, recipe string) (Upload, bool, error) {
+	u, err := scanUpload(s.db.QueryRow(
+		"SELECT "+uploadColumns+" FROM uploads WHERE player_id = ? AND game_id = ? AND recipe = ?",
+		playerID, gameID, recipe,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return u, false, nil
+	} else if err != nil {
+		return u, false, utils.ErrorWithTrace(err)
+	}
+	return u, true, nil
+}
+
+// RecordUpload inserts or updates a ledger entry, keeping its original
+// created_at.
+func (s *Store) RecordUpload(u Upload) error {
+	_, err := s.db.Exec(
+		`INSERT INTO uploads (
+			player_id,
+			game_id,
+			recipe,
+			video_id,
+			title,
+			description_hash,
+			status
+			) VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (player_id, game_id, recipe) DO UPDATE SET
+			video_id = excluded.video_id,
+			title = excluded.title,
+			description_hash = excluded.description_hash,
+			status = excluded.status,
+			updated_at = CURRENT_TIMESTAMP`,
+		u.PlayerID, u.GameID, u.Recipe, u.VideoID, u.Title, u.DescriptionHash, string(u.Status),
+	)
+	if err != nil {
+		return fmt.Errorf("error recording upload: %v", utils.ErrorWithTrace(err))
+	}
+	return nil
+}
+
+// Uploads returns the whole ledger, most recently updated first.
+func (s *Store) Uploads() ([]Upload, error) {
+	rows, err := s.db.Query("SELECT " + uploadColumns + " FROM uploads ORDER BY updated_at DESC")
+	if err != nil {
+		return nil, utils.ErrorWithTrace(err)
+	}
+	defer rows.Close()
+
+	uploads := []Upload{}
+	for rows.Next() {
+		u, err := scanUpload(rows)
+		if err != nil {
+			return nil, utils.ErrorWithTrace(err)
+		}
+		uploads = append(uploads, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, utils.ErrorWithTrace(err)
+	}
+	return uploads, nil
+}
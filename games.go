@@ -0,0 +1,97 @@
+package main
+
+import (
+	"basketball/nba"
+
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+type gameRow struct {
+	GameID  string
+	Date    string
+	Status  nba.GameStatus
+	Text    string
+	Away    string
+	AwayPts *float64
+	Home    string
+	HomePts *float64
+}
+
+// GamesOptions picks which games ListGames lists.
+type GamesOptions struct {
+	// Date is the day to list, unless Season is set
+	Date time.Time
+	// Season lists a whole season's schedule, e.g. 2024-25
+	Season string
+	// Team only lists games with this tricode in them
+	Team string
+	// Status only lists games that are scheduled, live or final
+	Status nba.GameStatus
+}
+
+// ListGames prints the league's games on a date or in a season.
+func ListGames(opts GamesOptions) error {
+	rows := []gameRow{}
+	if opts.Season != "" {
+		schedule, err := nba.ScheduleLeagueV2(league, opts.Season)
+		if err != nil {
+			return err
+		}
+		for _, g := range schedule.Games() {
+			if opts.Team != "" && !g.HasTeam(opts.Team) {
+				continue
+			}
+			day, _ := g.Date()
+			rows = append(rows, gameRow{
+				GameID:  deref(g.GameId),
+				Date:    day.Format("2006-01-02"),
+				Status:  g.Status(),
+				Text:    deref(g.GameStatusText),
+				Away:    deref(g.AwayTeam.TeamTricode),
+				AwayPts: g.AwayTeam.Score,
+				Home:    deref(g.HomeTeam.TeamTricode),
+				HomePts: g.HomeTeam.Score,
+			})
+		}
+	} else {
+		scoreboard, err := nba.ScoreboardV3(league, opts.Date)
+		if err != nil {
+			return err
+		}
+		for _, g := range scoreboard.Games {
+			if opts.Team != "" && !g.HasTeam(opts.Team) {
+				continue
+			}
+			rows = append(rows, gameRow{
+				GameID:  deref(g.GameId),
+				Date:    opts.Date.Format("2006-01-02"),
+				Status:  g.Status(),
+				Text:    deref(g.GameStatusText),
+				Away:    deref(g.AwayTeam.TeamTricode),
+				AwayPts: g.AwayTeam.Score,
+				Home:    deref(g.HomeTeam.TeamTricode),
+				HomePts: g.HomeTeam.Score,
+			})
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "GAME ID\tDATE\tSTATUS\tAWAY\t\tHOME\t")
+	for _, r := range rows {
+		if opts.Status != nba.GameStatusUnknown && r.Status != opts.Status {
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.GameID, r.Date, r.Text, r.Away, score(r.AwayPts, r.Status), r.Home, score(r.HomePts, r.Status))
+	}
+	return w.Flush()
+}
+
+func score(pts *float64, status nba.GameStatus) string {
+	if pts == nil || status == nba.GameStatusScheduled {
+		return ""
+	}
+	return fmt.Sprintf("%d", int(*pts))
+}
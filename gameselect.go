@@ -0,0 +1,153 @@
+package main
+
+import (
+	"basketball/config"
+	"basketball/nba"
+
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	flag "github.com/spf13/pflag"
+)
+
+// GameSelector picks games out of the game finder's results. Every set field
+// narrows the selection; with nothing set it picks the latest game.
+type GameSelector struct {
+	GameID string
+	// Date is a day (2025-01-20) or an inclusive range of days
+	// (2025-01-01..2025-01-31). Either end of a range can be left open.
+	Date string
+	// Vs is the opponent's tricode.
+	Vs string
+	// Last keeps the N most recent games left after the other filters.
+	Last int
+}
+
+func gameSelectorFlags(flags *flag.FlagSet) *GameSelector {
+	s := &GameSelector{}
+	flags.StringVar(&s.GameID, "game-id", "", "select the game with this id")
+	flags.StringVar(&s.Date, "date", "", "select games on a day (YYYY-MM-DD) or in a range (YYYY-MM-DD..YYYY-MM-DD)")
+	flags.StringVar(&s.Vs, "vs", "", "select games against this opponent's tricode (e.g. BOS)")
+	flags.IntVar(&s.Last, "last", 0, "select the N most recent games (default 1 when no other filter is set)")
+	return s
+}
+
+func (s GameSelector) isZero() bool {
+	return s.GameID == "" && s.Date == "" && s.Vs == "" && s.Last == 0
+}
+
+// Validate checks the selector's flags before anything is fetched.
+func (s GameSelector) Validate() error {
+	if s.Last < 0 {
+		return usageErrorf("--last must be positive, got %d", s.Last)
+	}
+	if _, _, err := s.dateRange(); err != nil {
+		return &exitError{ExitUsage, err}
+	}
+	return nil
+}
+
+// season is the season to ask the team game finder for. Dates can reach back
+// into earlier seasons, so selecting by date asks for every season.
+func (s GameSelector) season(l nba.League) string {
+	if s.GameID != "" {
+		if season, err := nba.SeasonFromGameID(s.GameID); err == nil {
+			return season
+		}
+	}
+	if s.Date != "" {
+		return ""
+	}
+	if config.Season != "" {
+		return config.Season
+	}
+	return l.CurrentSeason(time.Now())
+}
+
+// dateRange parses Date into inclusive bounds. Zero times are open ends.
+func (s GameSelector) dateRange() (from, to time.Time, err error) {
+	if s.Date == "" {
+		return from, to, nil
+	}
+	start, end, isRange := strings.Cut(s.Date, "..")
+	if !isRange {
+		end = start
+	}
+	if start != "" {
+		if from, err = time.Parse("2006-01-02", start); err != nil {
+			return from, to, fmt.Errorf("invalid --date %q, expected YYYY-MM-DD or YYYY-MM-DD..YYYY-MM-DD", s.Date)
+		}
+	}
+	if end != "" {
+		if to, err = time.Parse("2006-01-02", end); err != nil {
+			return from, to, fmt.Errorf("invalid --date %q, expected YYYY-MM-DD or YYYY-MM-DD..YYYY-MM-DD", s.Date)
+		}
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return from, to, fmt.Errorf("invalid --date %q, the range ends before it starts", s.Date)
+	}
+	return from, to, nil
+}
+
+// opponent reads the opponent's tricode out of a matchup, "NYK vs. BOS" and
+// "NYK @ BOS" are both against BOS.
+func opponent(matchup string) string {
+	fields := strings.Fields(matchup)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[len(fields)-1]
+}
+
+// Select returns the selected games, most recent first. It's an error for
+// nothing to match.
+func (s GameSelector) Select(games []nba.LeagueGameFinderGame) ([]nba.LeagueGameFinderGame, error) {
+	from, to, err := s.dateRange()
+	if err != nil {
+		return nil, err
+	}
+	// the game finder usually lists the latest game first, but --last and the
+	// default shouldn't depend on it
+	games = slices.Clone(games)
+	sort.SliceStable(games, func(i, j int) bool {
+		return deref(games[i].GameDate) > deref(games[j].GameDate)
+	})
+
+	selected := []nba.LeagueGameFinderGame{}
+	for _, g := range games {
+		if s.GameID != "" && (g.GameID == nil || *g.GameID != s.GameID) {
+			continue
+		}
+		if s.Vs != "" && (g.Matchup == nil || !strings.EqualFold(opponent(*g.Matchup), s.Vs)) {
+			continue
+		}
+		if s.Date != "" {
+			if g.GameDate == nil {
+				continue
+			}
+			day, err := time.Parse("2006-01-02", *g.GameDate)
+			if err != nil {
+				return nil, err
+			}
+			if (!from.IsZero() && day.Before(from)) || (!to.IsZero() && day.After(to)) {
+				continue
+			}
+		}
+		selected = append(selected, g)
+	}
+
+	last := s.Last
+	if s.isZero() {
+		last = 1
+	}
+	if last > 0 && last < len(selected) {
+		selected = selected[:last]
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no games match the selection")
+	}
+	return selected, nil
+}
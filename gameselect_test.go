@@ -0,0 +1,156 @@
+package main
+
+import (
+	"basketball/nba"
+
+	"slices"
+	"testing"
+	"time"
+)
+
+// finderGame is a game finder row with just enough filled in to select on.
+func finderGame(gameID, date, matchup string) nba.LeagueGameFinderGame {
+	return nba.LeagueGameFinderGame{GameID: ptr(gameID), GameDate: ptr(date), Matchup: ptr(matchup)}
+}
+
+func TestGameSelectorDateRange(t *testing.T) {
+	day := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	tests := []struct {
+		date     string
+		from, to time.Time
+		wantErr  bool
+	}{
+		{"", time.Time{}, time.Time{}, false},
+		{"2025-01-20", day("2025-01-20"), day("2025-01-20"), false},
+		{"2025-01-01..2025-01-31", day("2025-01-01"), day("2025-01-31"), false},
+		{"2025-01-01..", day("2025-01-01"), time.Time{}, false},
+		{"..2025-01-31", time.Time{}, day("2025-01-31"), false},
+		{"2025-01-31..2025-01-01", time.Time{}, time.Time{}, true},
+		{"01/20/2025", time.Time{}, time.Time{}, true},
+		{"2025-01-01..someday", time.Time{}, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		from, to, err := GameSelector{Date: tt.date}.dateRange()
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("dateRange(%q) = %v, %v, want an error", tt.date, from, to)
+			}
+			continue
+		}
+		if err != nil || !from.Equal(tt.from) || !to.Equal(tt.to) {
+			t.Errorf("dateRange(%q) = %v, %v, %v, want %v, %v", tt.date, from, to, err, tt.from, tt.to)
+		}
+	}
+}
+
+func TestGameSelectorSelect(t *testing.T) {
+	// most recent first, like the game finder
+	games := []nba.LeagueGameFinderGame{
+		finderGame("0022400040", "2025-01-25", "NYK vs. BOS"),
+		finderGame("0022400030", "2025-01-20", "NYK @ MIA"),
+		finderGame("0022400020", "2025-01-10", "NYK @ BOS"),
+		finderGame("0022400010", "2024-12-25", "NYK vs. SAS"),
+	}
+	tests := []struct {
+		name     string
+		selector GameSelector
+		want     []string
+		wantErr  bool
+	}{
+		{"latest by default", GameSelector{}, []string{"0022400040"}, false},
+		{"by game id", GameSelector{GameID: "0022400020"}, []string{"0022400020"}, false},
+		{"unknown game id", GameSelector{GameID: "0022400099"}, nil, true},
+		{"on a day", GameSelector{Date: "2025-01-20"}, []string{"0022400030"}, false},
+		{"no game that day", GameSelector{Date: "2025-01-21"}, nil, true},
+		{"in a range", GameSelector{Date: "2025-01-01..2025-01-20"}, []string{"0022400030", "0022400020"}, false},
+		{"open start", GameSelector{Date: "..2025-01-10"}, []string{"0022400020", "0022400010"}, false},
+		{"open end", GameSelector{Date: "2025-01-20.."}, []string{"0022400040", "0022400030"}, false},
+		{"vs home and away", GameSelector{Vs: "BOS"}, []string{"0022400040", "0022400020"}, false},
+		{"vs ignores case", GameSelector{Vs: "mia"}, []string{"0022400030"}, false},
+		{"last", GameSelector{Last: 3}, []string{"0022400040", "0022400030", "0022400020"}, false},
+		{"last more than there are", GameSelector{Last: 10}, []string{"0022400040", "0022400030", "0022400020", "0022400010"}, false},
+		{"last after other filters", GameSelector{Vs: "BOS", Last: 1}, []string{"0022400040"}, false},
+		{"filters combine", GameSelector{Vs: "BOS", Date: "..2025-01-20"}, []string{"0022400020"}, false},
+		{"bad date", GameSelector{Date: "yesterday"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := tt.selector.Select(games)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got %d games, want an error", len(selected))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, g := range selected {
+				got = append(got, *g.GameID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGameSelectorValidate(t *testing.T) {
+	tests := []struct {
+		selector GameSelector
+		wantErr  bool
+	}{
+		{GameSelector{}, false},
+		{GameSelector{Last: 5, Date: "2025-01-01.."}, false},
+		{GameSelector{Last: -1}, true},
+		{GameSelector{Date: "2025-13-01"}, true},
+	}
+	for _, tt := range tests {
+		err := tt.selector.Validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%+v: got %v, want error %v", tt.selector, err, tt.wantErr)
+		}
+		if err != nil && exitCodeOf(err) != ExitUsage {
+			t.Errorf("%+v: got exit code %d, want %d", tt.selector, exitCodeOf(err), ExitUsage)
+		}
+	}
+}
+
+func TestGameSelectorSelectIgnoresFinderOrder(t *testing.T) {
+	games := []nba.LeagueGameFinderGame{
+		finderGame("0022400010", "2024-12-25", "NYK vs. SAS"),
+		finderGame("0022400040", "2025-01-25", "NYK vs. BOS"),
+		finderGame("0022400020", "2025-01-10", "NYK @ BOS"),
+	}
+	tests := []struct {
+		selector GameSelector
+		want     []string
+	}{
+		{GameSelector{}, []string{"0022400040"}},
+		{GameSelector{Last: 2}, []string{"0022400040", "0022400020"}},
+		{GameSelector{Vs: "BOS"}, []string{"0022400040", "0022400020"}},
+	}
+	for _, tt := range tests {
+		selected, err := tt.selector.Select(games)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, g := range selected {
+			got = append(got, *g.GameID)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%+v: got %v, want %v", tt.selector, got, tt.want)
+		}
+	}
+	if *games[0].GameID != "0022400010" {
+		t.Error("Select reordered the caller's games")
+	}
+}
@@ -4,9 +4,11 @@ go 1.23.0
 
 require (
 	github.com/golang-migrate/migrate/v4 v4.18.2
+	github.com/mattn/go-sqlite3 v1.14.28
 	github.com/spf13/pflag v1.0.6
 	golang.org/x/oauth2 v0.29.0
 	google.golang.org/api v0.229.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -22,7 +24,6 @@ require (
 	github.com/googleapis/gax-go/v2 v2.14.1 // indirect
 	github.com/hashicorp/errwrap v1.1.0 // indirect
 	github.com/hashicorp/go-multierror v1.1.1 // indirect
-	github.com/mattn/go-sqlite3 v1.14.28 // indirect
 	go.opentelemetry.io/auto/sdk v1.1.0 // indirect
 	go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp v0.60.0 // indirect
 	go.opentelemetry.io/otel v1.35.0 // indirect
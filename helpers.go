@@ -0,0 +1,16 @@
+package main
+
+// ptr returns a pointer to v, e.g. for filling in the nba package's optional
+// fields.
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// deref returns what p points at, or the zero value when p is nil.
+func deref[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
@@ -0,0 +1,376 @@
+package main
+
+import (
+	"basketball/db"
+	"basketball/nba"
+	"basketball/youtube"
+
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	yt "google.golang.org/api/youtube/v3"
+)
+
+var Recipes = map[string]Recipe{
+	HighlightsRecipe.Name: HighlightsRecipe,
+}
+
+// queueJob adds a job for the player's reel to the queue, or returns the job
+// already queued for it by an earlier run.
+func queueJob(playerName string, game nba.LeagueGameFinderGame, recipe Recipe) (db.Job, error) {
+	return store.EnsureJob(db.Job{
+		PlayerID:   int(*game.PlayerId),
+		PlayerName: playerName,
+		GameID:     *game.GameID,
+		Recipe:     recipe.Name,
+		Game:       game,
+	})
+}
+
+// runJob runs the job's remaining stages until it reaches stop, persisting the
+// job after every stage. A failed stage leaves the job at that stage so the
+// next run retries it without redoing the stages that already completed.
+func runJob(job *db.Job, stop db.JobStage) error {
+	if job.Status == db.JobStatusCancelled {
+		return fmt.Errorf("job %d is cancelled", job.ID)
+	}
+	recipe, ok := Recipes[job.Recipe]
+	if !ok {
+		return fmt.Errorf("job %d has unknown recipe %q", job.ID, job.Recipe)
+	}
+
+	for job.Stage != db.JobStageDone && job.Stage != stop {
+		job.Status = db.JobStatusRunning
+		if err := store.UpdateJob(*job); err != nil {
+			return err
+		}
+
+		// jobs run on worker goroutines, where a panicking nba request would
+		// take every other job down with it, so it fails the stage instead
+		err := catch(func() error { return runStage(job, recipe) })
+		if err != nil {
+			job.Status = db.JobStatusFailed
+			if errors.Is(err, errSkipPlayer) {
+				job.Status = db.JobStatusCancelled
+			}
+			job.Error = err.Error()
+			if updateErr := store.UpdateJob(*job); updateErr != nil {
+				progress.Println(updateErr)
+			}
+			return err
+		}
+		job.Error = ""
+	}
+
+	if job.Stage == db.JobStageDone {
+		job.Status = db.JobStatusDone
+	} else {
+		job.Status = db.JobStatusPending
+	}
+	return store.UpdateJob(*job)
+}
+
+func runStage(job *db.Job, recipe Recipe) error {
+	switch job.Stage {
+	case db.JobStageFetch:
+		return fetchStage(job, recipe)
+	case db.JobStageDownload:
+		return downloadStage(job, recipe)
+	case db.JobStageRender:
+		return renderStage(job, recipe)
+	case db.JobStageUpload:
+		return uploadStage(job, recipe)
+	}
+	return fmt.Errorf("unknown stage %q", job.Stage)
+}
+
+func fetchStage(job *db.Job, recipe Recipe) error {
+	assets, err := getVideoAssets(job.Game, recipe.Measures)
+	if err != nil {
+		return err
+	}
+	if err := sortAssets(&assets); err != nil {
+		return err
+	}
+	if err := store.SetJobClips(job.ID, assets); err != nil {
+		return err
+	}
+	job.ClipCount = len(assets)
+	job.Stage = db.JobStageDownload
+	return nil
+}
+
+// jobAssets reads the clips found by the fetch stage back out of the clip
+// catalog in reel order.
+func jobAssets(job *db.Job, recipe Recipe) ([]nba.VideoDetailAsset, error) {
+	assets, err := store.JobClips(job.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(assets) == 0 && job.ClipCount > 0 {
+		// jobs fetched before their clips were kept have only the catalog,
+		// which is right as long as it hasn't grown since
+		if assets, err = store.Clips(job.GameID, job.PlayerID, recipe.Measures...); err != nil {
+			return nil, err
+		}
+		if err := sortAssets(&assets); err != nil {
+			return nil, err
+		}
+	}
+	if len(assets) != job.ClipCount {
+		return nil, fmt.Errorf("expected %d cataloged clips, found %d", job.ClipCount, len(assets))
+	}
+	return assets, nil
+}
+
+func downloadStage(job *db.Job, recipe Recipe) error {
+	assets, err := jobAssets(job, recipe)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(job.TmpDir); job.TmpDir == "" || err != nil {
+		tmpDir, err := mkdirTmp(job.PlayerName, &job.Game)
+		if err != nil {
+			return err
+		}
+		job.TmpDir = tmpDir
+		if err := store.UpdateJob(*job); err != nil {
+			return err
+		}
+	}
+	if err := downloadAssets(job.PlayerName, &assets, job.TmpDir); err != nil {
+		return err
+	}
+	job.Stage = db.JobStageRender
+	return nil
+}
+
+func renderStage(job *db.Job, recipe Recipe) error {
+	assets, err := jobAssets(job, recipe)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(job.OutputFile); job.OutputFile == "" || err != nil {
+		outputFile, err := ffmpeg(job.PlayerName, job.TmpDir, job.ClipCount, clipsDuration(assets))
+		if err != nil {
+			return err
+		}
+		if outputFile, err = placeReel(outputFile, job.Game, recipe); err != nil {
+			return err
+		}
+		// the reel is in place, so a retry must not render and place it again
+		job.OutputFile = outputFile
+		if err := store.UpdateJob(*job); err != nil {
+			return err
+		}
+	}
+	_ = os.RemoveAll(job.TmpDir)
+	job.TmpDir = ""
+
+	// the sidecar and shot chart are nice to have, the reel is what matters
+	statline, err := statString(job.Game, seasonGameLog(job.Game))
+	if err == nil {
+		err = writeSidecar(job.OutputFile, job.Game, recipe, assets, statline)
+	}
+	if err != nil {
+		progress.Println("failed to write sidecar for", job.PlayerName)
+		progress.Println(err)
+	}
+	if _, err := renderShotChart(job.Game, job.OutputFile); err != nil {
+		progress.Println("failed to render shot chart for", job.PlayerName)
+		progress.Println(err)
+	}
+	job.Stage = db.JobStageUpload
+	return nil
+}
+
+var youtubeService struct {
+	once    sync.Once
+	service *yt.Service
+	err     error
+}
+
+func getYoutubeService() (*yt.Service, error) {
+	youtubeService.once.Do(func() {
+		youtubeService.service, youtubeService.err = youtube.GetService()
+	})
+	return youtubeService.service, youtubeService.err
+}
+
+func uploadStage(job *db.Job, recipe Recipe) error {
+	game := job.Game
+	title, err := title(game)
+	if err != nil {
+		return fmt.Errorf("failed while trying to generate youtube video title: %v", err)
+	}
+	statline, err := reelStatline(game, job.OutputFile)
+	if err != nil {
+		return fmt.Errorf("failed while trying to generate youtube video description: %v", err)
+	}
+	description := videoDescription(game, statline)
+
+	upload := db.Upload{
+		PlayerID:        job.PlayerID,
+		GameID:          job.GameID,
+		Recipe:          recipe.Name,
+		Title:           title,
+		DescriptionHash: fmt.Sprintf("%x", sha256.Sum256([]byte(description))),
+	}
+	previous, found, err := store.Upload(upload.PlayerID, upload.GameID, upload.Recipe)
+	if err != nil {
+		return fmt.Errorf("failed to check the upload ledger: %v", err)
+	}
+	// an uploading row is left behind by a run that died part way, nothing
+	// else uploads the same reel at the same time. Without a video id the
+	// upload never got through and is retried like a failed one; with one the
+	// video is up and only needs finishing off.
+	if force {
+		// --force uploads again whatever the ledger says
+		found = false
+	}
+	switch {
+	case found && previous.Status == db.UploadStatusUploaded:
+		progress.Printf("skipping %s: already uploaded as %s (use --force to upload again)\n", title, previous.VideoID)
+	case found && previous.Status == db.UploadStatusUploading && previous.VideoID != "":
+		progress.Printf("finishing %s: uploaded as %s by a run that stopped before it was done\n", title, previous.VideoID)
+		upload.VideoID = previous.VideoID
+		if err := finishUpload(job, upload); err != nil {
+			return err
+		}
+	default:
+		if found && previous.Status == db.UploadStatusUploading {
+			progress.Printf("retrying %s: an earlier upload never finished\n", title)
+		}
+		service, err := getYoutubeService()
+		if err != nil {
+			return err
+		}
+
+		upload.Status = db.UploadStatusUploading
+		if err := store.RecordUpload(upload); err != nil {
+			return err
+		}
+		size := int64(0)
+		if info, err := os.Stat(job.OutputFile); err == nil {
+			size = info.Size()
+		}
+		progress.Println("Uploading to youtube...")
+		task := progress.Start("upload "+job.PlayerName, ProgressBytes, size)
+		videoID, err := youtube.UploadFile(job.OutputFile, title, description, *game.PlayerName, *game.TeamName, service, task.Set)
+		task.Done(err)
+		if err != nil {
+			upload.Status = db.UploadStatusFailed
+			if err := store.RecordUpload(upload); err != nil {
+				progress.Println(err)
+			}
+			return err
+		}
+		progress.Println("Upload successful :D!", title, videoID)
+		// the video is up, so remember it before anything else can fail
+		upload.VideoID = videoID
+		if err := store.RecordUpload(upload); err != nil {
+			return err
+		}
+		if err := finishUpload(job, upload); err != nil {
+			return err
+		}
+	}
+
+	// the reel and its sidecar stay where --out put them, only working files go
+	if job.TmpDir != "" {
+		_ = os.RemoveAll(job.TmpDir)
+		job.TmpDir = ""
+	}
+	job.Stage = db.JobStageDone
+	return nil
+}
+
+// finishUpload sets an uploaded video's shot chart thumbnail and marks it
+// uploaded in the ledger. A thumbnail that fails to set is reported but
+// doesn't fail the upload.
+func finishUpload(job *db.Job, upload db.Upload) error {
+	service, err := getYoutubeService()
+	if err != nil {
+		return err
+	}
+	thumbnail := shotChartBase(job.OutputFile) + ".png"
+	if _, err := os.Stat(thumbnail); err == nil {
+		if err := youtube.SetThumbnail(upload.VideoID, thumbnail, service); err != nil {
+			progress.Println("failed to set shot chart thumbnail for", upload.Title)
+			progress.Println(err)
+		}
+	}
+	upload.Status = db.UploadStatusUploaded
+	return store.RecordUpload(upload)
+}
+
+const jobsUsage = `commands:
+  list          list every job in the queue
+  retry ID      resume a job from the stage it stopped at and run it to completion
+  cancel ID     cancel a job and delete its working files`
+
+func Jobs(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("jobs requires a command\n\n%s", jobsUsage)
+	}
+	switch args[0] {
+	case "list":
+		jobs, err := store.Jobs()
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tSTATUS\tSTAGE\tPLAYER\tGAME ID\tRECIPE\tUPDATED\tERROR")
+		for _, j := range jobs {
+			errLine, _, _ := strings.Cut(j.Error, "\n")
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", j.ID, j.Status, j.Stage, j.PlayerName, j.GameID, j.Recipe, j.UpdatedAt, errLine)
+		}
+		return w.Flush()
+	case "retry", "cancel":
+		if len(args) < 2 {
+			return usageErrorf("%s requires a job id\n\n%s", args[0], jobsUsage)
+		}
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return usageErrorf("invalid job id %q: %v", args[1], err)
+		}
+		job, err := store.Job(id)
+		if err != nil {
+			return err
+		}
+		if args[0] == "cancel" {
+			return cancelJob(&job)
+		}
+		if job.Status == db.JobStatusCancelled {
+			job.Status = db.JobStatusPending
+		}
+		if err := runJob(&job, db.JobStageDone); err != nil {
+			return err
+		}
+		fmt.Printf("job %d is %s\n", job.ID, job.Status)
+	default:
+		return usageErrorf("unknown jobs command %q\n\n%s", args[0], jobsUsage)
+	}
+	return nil
+}
+
+// cancelJob stops the job and deletes its working files. A reel it already
+// rendered is the user's and is left alone.
+func cancelJob(job *db.Job) error {
+	if job.TmpDir != "" {
+		_ = os.RemoveAll(job.TmpDir)
+		job.TmpDir = ""
+	}
+	job.Status = db.JobStatusCancelled
+	if err := store.UpdateJob(*job); err != nil {
+		return err
+	}
+	fmt.Printf("job %d cancelled\n", job.ID)
+	return nil
+}
@@ -4,10 +4,12 @@ import (
 	"basketball/config"
 	"basketball/db"
 	"basketball/nba"
-	"basketball/youtube"
+	"basketball/shotchart"
 
+	"bufio"
 	"crypto/md5"
 	_ "embed"
+	"errors"
 	"fmt"
 	"io"
 	"math"
@@ -15,230 +17,395 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"text/tabwriter"
 	"time"
-
-	flag "github.com/spf13/pflag"
 )
 
-var statlinePlayerName string
-var videoPlayerName string
-var Knicks *bool
+// league is the league every player, team and game lookup is made in, set by
+// each command's --league flag.
+var league = nba.LeagueNBA
+
+// force uploads videos even if the upload ledger says they were already
+// published.
+var force bool
 
-func init() {
-	flag.StringVarP(&statlinePlayerName, "statline", "s", "", "player name to get statline for")
-	flag.StringVarP(&videoPlayerName, "video", "v", "", "player name to get video of")
-	Knicks = flag.BoolP("knicks", "k", false, "downloads and uploads all knicks highlights")
-	flag.Parse()
+// Recipe names the set of context measures a reel is cut from. The name is
+// part of what identifies a reel in the upload ledger.
+type Recipe struct {
+	Name     string
+	Measures []nba.VideoDetailsAssetContextMeasure
+}
+
+var HighlightsRecipe = Recipe{
+	Name: "highlights",
+	Measures: []nba.VideoDetailsAssetContextMeasure{
+		nba.VideoDetailsAssetContextMeasures.FGA,
+		nba.VideoDetailsAssetContextMeasures.REB,
+		nba.VideoDetailsAssetContextMeasures.AST,
+		nba.VideoDetailsAssetContextMeasures.STL,
+		nba.VideoDetailsAssetContextMeasures.TOV,
+		nba.VideoDetailsAssetContextMeasures.BLK,
+	},
 }
 
-func main() {
-	config.LoadConfig()
-	db.SetupDatabase()
-	db.RunMigrations()
-	db.ValidateMigrations()
+var store *db.Store
+
+const dbUsage = `commands:
+  up            apply all pending migrations
+  down [N]      roll back the last N migrations (default 1)
+  version       print the current migration version
+  force V       set the migration version to V without running migrations
+  validate      check the seeded teams are where they should be`
 
-	// scrapeCommonAllPlayers()
-	if *Knicks {
-		Knickerbockers()
+func DB(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("db requires a command\n\n%s", dbUsage)
 	}
-	if len(videoPlayerName) != 0 {
-		videoRes, err := Video(videoPlayerName, true)
+	switch args[0] {
+	case "up":
+		if err := store.MigrateUp(); err != nil {
+			return err
+		}
+		fmt.Println("migrations applied")
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil {
+				return usageErrorf("invalid number of steps %q: %v", args[1], err)
+			}
+			steps = n
+		}
+		if err := store.MigrateDown(steps); err != nil {
+			return err
+		}
+		fmt.Printf("rolled back %d migration(s)\n", steps)
+	case "version":
+		version, dirty, err := store.MigrationVersion()
 		if err != nil {
-			panic(err)
+			return err
 		}
-		printStatline(videoRes.Game)
-	}
-	if len(statlinePlayerName) != 0 {
-		if err := Statline(statlinePlayerName); err != nil {
-			panic(err)
+		if dirty {
+			fmt.Printf("%d (dirty)\n", version)
+		} else {
+			fmt.Println(version)
 		}
+	case "force":
+		if len(args) < 2 {
+			return usageErrorf("force requires a version\n\n%s", dbUsage)
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return usageErrorf("invalid version %q: %v", args[1], err)
+		}
+		if err := store.ForceMigration(version); err != nil {
+			return err
+		}
+		fmt.Printf("forced migration version to %d\n", version)
+	case "validate":
+		return store.ValidateMigrations()
+	default:
+		return usageErrorf("unknown db command %q\n\n%s", args[0], dbUsage)
 	}
+	return nil
 }
 
-const KnicksTeamId = 1610612752
+// homeTeams are the tricodes of the team team-reel makes reels for in each
+// league when --team isn't given.
+var homeTeams = map[nba.League]string{
+	nba.LeagueNBA:     "NYK",
+	nba.LeagueWNBA:    "NYL",
+	nba.LeagueGLeague: "WES",
+}
 
-func Knickerbockers() {
-	var input string
-	fmt.Println("finding non-situational players...")
-	games := nba.LeagueGameFinderByTeamID(KnicksTeamId)
-	gameNum := 0
-	game := games[gameNum]
-	fmt.Println(*game.Matchup)
-	boxscore, err := nba.BoxScoreTraditionalV3(*game.GameID)
-	if err != nil {
-		panic(err)
+// resolveTeam finds a team in the current league by id, tricode or name. Only
+// the NBA teams are seeded, so other leagues' teams are scraped along with
+// their players the first time they're needed.
+func resolveTeam(query string) (db.Team, error) {
+	team, err := store.FindTeam(league, query)
+	if err == nil || league == nba.LeagueNBA {
+		return team, err
 	}
-
-	wg := sync.WaitGroup{}
-	playerGameMap := map[string]nba.LeagueGameFinderGame{}
-	errMap := sync.Map{}
-	videoMap := sync.Map{}
-
-	var knicksPlayers []nba.BoxScoreTraditionalV3Player
-	if *boxscore.HomeTeamId == KnicksTeamId {
-		knicksPlayers = boxscore.HomeTeam.Players
-	}else {
-		knicksPlayers = boxscore.AwayTeam.Players
+	if err := scrapeCommonAllPlayers(); err != nil {
+		return team, err
 	}
+	return store.FindTeam(league, query)
+}
 
-	nonSituational := []nba.BoxScoreTraditionalV3Player{}
-	for _, p := range knicksPlayers {
-		if p.DidNotPlay() {
-			continue
-		}
-		nonSituational = append(nonSituational, p)
+// lookupPlayerID looks a player up by name in the current league, scraping the
+// league's players if they aren't there yet.
+func lookupPlayerID(playerCode string) (int, error) {
+	id, err := store.PlayerIDFromCode(league, playerCode)
+	if err == nil {
+		return id, nil
 	}
+	if scrapeErr := scrapeCommonAllPlayers(); scrapeErr != nil {
+		return 0, scrapeErr
+	}
+	return store.PlayerIDFromCode(league, playerCode)
+}
 
-	measures := []nba.VideoDetailsAssetContextMeasure{
-		nba.VideoDetailsAssetContextMeasures.FGA,
-		nba.VideoDetailsAssetContextMeasures.REB,
-		nba.VideoDetailsAssetContextMeasures.AST,
-		nba.VideoDetailsAssetContextMeasures.STL,
-		nba.VideoDetailsAssetContextMeasures.TOV,
-		nba.VideoDetailsAssetContextMeasures.BLK,
+// TeamReel makes a reel for every player who got in the team's selected
+// games, then asks to upload them.
+func TeamReel(teamQuery string, selector GameSelector) error {
+	team, err := resolveTeam(teamQuery)
+	if err != nil {
+		return err
+	}
+	games := nba.LeagueGameFinderByTeamID(league, team.ID, selector.season(league))
+	if len(games) == 0 {
+		return fmt.Errorf("no %s games found for the %s", league, team.Name)
+	}
+	recordGames(games)
+	selected, err := selector.Select(games)
+	if err != nil {
+		return fmt.Errorf("%s: %v", team.Name, err)
 	}
 
-	fmt.Printf("%d non-situational players\n", len(nonSituational))
-	fmt.Println("querying for asset urls...")
-	teamAssets := map[string][]nba.VideoDetailAsset{}
-	for _, p := range nonSituational {
-		id := int(*p.PersonId)
-		games, err := nba.LeagueGameFinderByPlayerID(id)
+	jobs := []*db.Job{}
+	finder := map[int][]nba.LeagueGameFinderGame{}
+	for _, game := range selected {
+		gameJobs, _, err := queueTeamGame(team.ID, game, HighlightsRecipe, finder)
 		if err != nil {
-			fmt.Println(err)
+			progress.Eprintln(*game.Matchup, err)
 			continue
 		}
-		playerGame := games[gameNum]
-		playerName := *p.FirstName + *p.FamilyName
-		playerGameMap[playerName] = playerGame
+		jobs = append(jobs, gameJobs...)
+	}
+	return runTeamJobs(jobs)
+}
 
-		pAssets, err := getVideoAssets(playerGame, measures)
-		if err != nil {
-			fmt.Println(playerName, err)
-			continue
-		}
-		if err := sortAssets(&pAssets); err != nil {
-			fmt.Println(playerName, err)
-			continue
+// runTeamJobs finds the jobs' clips one at a time, since that can prompt, then
+// downloads and renders them config.Concurrency at a time and asks to upload
+// them.
+func runTeamJobs(jobs []*db.Job) error {
+	wg := sync.WaitGroup{}
+	errMap := sync.Map{}
+	// limits how many jobs download, render or upload at once
+	sem := make(chan struct{}, config.Concurrency)
+
+	fmt.Println("querying for asset urls...")
+	for _, job := range jobs {
+		if err := runJob(job, db.JobStageDownload); err != nil {
+			progress.Eprintln(job.PlayerName, err)
 		}
-		teamAssets[playerName] = pAssets
 	}
 
-	fmt.Println("download clips? (y/n)")
-	_, err = fmt.Scan(&input)
+	download, err := confirm("download clips?")
 	if err != nil {
-		panic(err)
+		return err
 	}
-	if !regexp.MustCompile("^[yY]").Match([]byte(input)) {
-		return
+	if !download {
+		return nil
 	}
 
 	fmt.Println("downloading clips and concatenating...")
-	for k, v := range teamAssets {
+	for _, job := range jobs {
+		if job.Status == db.JobStatusFailed || job.Status == db.JobStatusCancelled {
+			continue
+		}
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			tmpDir, err := mkdirTmp(k, &game)
-			if err != nil {
-				errMap.Store(k, err)
-				return
-			}
-			if err := downloadAssets(&v, tmpDir); err != nil {
-				errMap.Store(k, err)
-				_ = os.RemoveAll(tmpDir)
-				return
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if err := runJob(job, db.JobStageUpload); err != nil {
+				errMap.Store(job.PlayerName, err)
 			}
-			outputFile, err := ffmpeg(tmpDir, len(v))
-			if err != nil {
-				errMap.Store(k, err)
-				_ = os.RemoveAll(tmpDir)
-				_ = os.RemoveAll(outputFile)
-				return
-			}
-			videoMap.Store(k, VideoRes{Game: playerGameMap[k], OutputFile: outputFile})
 		}()
 	}
-
 	wg.Wait()
-	defer func() {
-		videoMap.Range(func(key, value any) bool {
-			videoRes := value.(VideoRes)
-			_ = os.Remove(videoRes.OutputFile)
-			return true
-		})
-	}()
-
-	fmt.Println("Displaying errors...")
+
 	errMap.Range(func(key, value any) bool {
-		fmt.Println("player error:", key.(string))
-		fmt.Println(value.(error))
+		progress.Eprintln("player error:", key.(string))
+		progress.Eprintln(value.(error))
 		return true
 	})
 
-	fmt.Println("Upload to Youtube? (y/n)")
-	_, err = fmt.Scan(&input)
+	if noUpload {
+		fmt.Println("not uploading (--no-upload), run basketball upload to upload the rendered reels")
+		return nil
+	}
+	upload, err := confirm("Upload to Youtube?")
 	if err != nil {
-		panic(err)
+		return err
+	}
+	if !upload {
+		return nil
 	}
 
-	if !regexp.MustCompile("^[yY]").Match([]byte(input)) {
-		return
+	failed := 0
+	failedMu := sync.Mutex{}
+	for _, job := range jobs {
+		if job.Status == db.JobStatusFailed || job.Status == db.JobStatusCancelled {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if err := runJob(job, db.JobStageDone); err != nil {
+				progress.Eprintln("failed to upload", job.PlayerName)
+				progress.Eprintln(err)
+				failedMu.Lock()
+				failed++
+				failedMu.Unlock()
+			}
+		}()
 	}
+	wg.Wait()
+	if failed > 0 {
+		return fmt.Errorf("%d uploads failed", failed)
+	}
+	return nil
+}
 
-	service, err := youtube.GetService()
+// queueTeamGame queues a job for each of the team's players who got in the
+// game. A player's latest game isn't the team's when they've sat out since,
+// so their game is found by id in their own game finder results, which are
+// cached in finder across games. missing names the players who got in but
+// couldn't be queued yet, usually because their game log lags the team's.
+func queueTeamGame(teamID int, game nba.LeagueGameFinderGame, recipe Recipe, finder map[int][]nba.LeagueGameFinderGame) (jobs []*db.Job, missing []string, err error) {
+	progress.Println(*game.Matchup)
+	if summary, err := gameSummary(*game.GameID); err != nil {
+		progress.Eprintln("failed to fetch game summary:", err)
+	} else if lineScore := lineScoreString(summary); lineScore != "" {
+		progress.Println(lineScore)
+	}
+	boxscore, err := nba.BoxScoreTraditionalV3(*game.GameID)
 	if err != nil {
-		panic(err)
+		return nil, nil, err
+	}
+
+	if err := syncRoster(teamID, game); err != nil {
+		progress.Eprintln("failed to sync roster:", err)
 	}
 
-	videoMap.Range(func(playerName, value any) bool {
-		videoRes := value.(VideoRes)
-		game := videoRes.Game
-		title, err := title(game)
+	progress.Println("finding non-situational players...")
+	var teamPlayers []nba.BoxScoreTraditionalV3Player
+	if int(*boxscore.HomeTeamId) == teamID {
+		teamPlayers = boxscore.HomeTeam.Players
+	} else {
+		teamPlayers = boxscore.AwayTeam.Players
+	}
+
+	nonSituational := []nba.BoxScoreTraditionalV3Player{}
+	for _, p := range teamPlayers {
+		if p.DidNotPlay() {
+			continue
+		}
+		nonSituational = append(nonSituational, p)
+	}
+
+	progress.Printf("%d non-situational players\n", len(nonSituational))
+	progress.Println("queueing jobs...")
+	jobs = []*db.Job{}
+	for _, p := range nonSituational {
+		id := int(*p.PersonId)
+		playerName := *p.FirstName + *p.FamilyName
+		games, ok := finder[id]
+		if !ok {
+			games, err = nba.LeagueGameFinderByPlayerID(league, id)
+			if err != nil {
+				progress.Eprintln(playerName, err)
+				missing = append(missing, playerName)
+				continue
+			}
+			finder[id] = games
+		}
+		playerGame, ok := findGame(games, *game.GameID)
+		if !ok {
+			progress.Eprintf("skipping %s: game %s isn't in their game log yet\n", playerName, *game.GameID)
+			missing = append(missing, playerName)
+			continue
+		}
+
+		job, err := queueJob(playerName, playerGame, recipe)
 		if err != nil {
-			fmt.Println("failed while trying to generate youtube video title for", playerName.(string))
-			fmt.Println(err)
-			return true
+			progress.Eprintln(playerName, err)
+			missing = append(missing, playerName)
+			continue
+		}
+		switch job.Status {
+		case db.JobStatusDone, db.JobStatusCancelled:
+			progress.Printf("skipping %s: job %d is %s\n", playerName, job.ID, job.Status)
+			continue
+		}
+		jobs = append(jobs, &job)
+	}
+	return jobs, missing, nil
+}
+
+func findGame(games []nba.LeagueGameFinderGame, gameID string) (nba.LeagueGameFinderGame, bool) {
+	for _, g := range games {
+		if g.GameID != nil && *g.GameID == gameID {
+			return g, true
 		}
-		description, err := statString(game)
+	}
+	return nba.LeagueGameFinderGame{}, false
+}
+
+const listUsage = `what:
+  uploads       every video recorded in the upload ledger`
+
+func List(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("list requires something to list\n\n%s", listUsage)
+	}
+	switch args[0] {
+	case "uploads":
+		uploads, err := store.Uploads()
 		if err != nil {
-			fmt.Println("failed while trying to generate youtube video description for", playerName.(string))
-			fmt.Println(err)
-			return true
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "STATUS\tVIDEO ID\tPLAYER ID\tGAME ID\tRECIPE\tUPDATED\tTITLE")
+		for _, u := range uploads {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\t%s\n", u.Status, u.VideoID, u.PlayerID, u.GameID, u.Recipe, u.UpdatedAt, u.Title)
 		}
+		return w.Flush()
+	default:
+		return usageErrorf("unknown list %q\n\n%s", args[0], listUsage)
+	}
+}
 
-		wg.Add(1)
-		go func() {
-			defer func() { wg.Done() }()
-			youtube.UploadFile(videoRes.OutputFile, title, description, *game.PlayerName, *game.TeamName, service)
-			_ = os.Remove(videoRes.OutputFile)
-		}()
-		return true
-	})
-	wg.Wait()
+func scrapeCommonAllPlayers() error {
+	players := nba.CommonAllPlayers(league)
+	return store.InsertPlayers(league, players)
 }
 
-func scrapeCommonAllPlayers() {
-	players := nba.CommonAllPlayers()
-	db.InsertPlayers(players)
+// recordGames keeps the games table up to date with whatever the game finder
+// returned. Failing to record games should never stop a statline or a video.
+func recordGames(games []nba.LeagueGameFinderGame) {
+	if err := store.UpsertGames(games); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to record games:", err)
+	}
 }
 
-func Statline(playerCode string) error {
-	id, err := db.PlayerIDFromCode(playerCode)
+// playerGames finds a player's games and narrows them down to the selected
+// ones.
+func playerGames(playerCode string, selector GameSelector) ([]nba.LeagueGameFinderGame, error) {
+	id, err := lookupPlayerID(playerCode)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	games, err := nba.LeagueGameFinderByPlayerID(id)
+	games, err := nba.LeagueGameFinderByPlayerID(league, id)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	game := games[0]
-	printStatline(game)
-	return nil
+	recordGames(games)
+	selected, err := selector.Select(games)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", playerCode, err)
+	}
+	return selected, nil
 }
 
 func printStatline(game nba.LeagueGameFinderGame) {
@@ -248,7 +415,7 @@ func printStatline(game nba.LeagueGameFinderGame) {
 	if err != nil {
 		panic(err)
 	}
-	statline, err := statString(game)
+	statline, err := statString(game, seasonGameLog(game))
 	if err != nil {
 		panic(err)
 	}
@@ -256,7 +423,28 @@ func printStatline(game nba.LeagueGameFinderGame) {
 	fmt.Println(statline)
 }
 
-func statString(game nba.LeagueGameFinderGame) (string, error) {
+// seasonGameLog is the player's game log for the season the game was played
+// in. Annotations are a nice to have, so failures are printed and nil returned.
+func seasonGameLog(game nba.LeagueGameFinderGame) []nba.PlayerGameLogGame {
+	gameLeague := nba.LeagueFromGameID(*game.GameID)
+	season, err := nba.SeasonFromID(gameLeague, *game.SeasonID)
+	if err != nil {
+		progress.Eprintln(err)
+		return nil
+	}
+	log, err := nba.PlayerGameLog(gameLeague, int(*game.PlayerId), season, nba.SeasonTypeFromGameID(*game.GameID))
+	if err != nil {
+		progress.Eprintln("failed to fetch game log for", *game.PlayerName)
+		progress.Eprintln(err)
+		return nil
+	}
+	return log
+}
+
+// statString formats the game's statline. When a game log is given, points,
+// rebounds and assists are compared against the games played before this one,
+// e.g. "38 Points (season-high, +12 over season average)".
+func statString(game nba.LeagueGameFinderGame, log []nba.PlayerGameLogGame) (string, error) {
 	statStrings := []string{
 		"Point",
 		"Rebound",
@@ -281,8 +469,29 @@ func statString(game nba.LeagueGameFinderGame) (string, error) {
 		return "", fmt.Errorf("length of stats (%d) != length of statStrings (%d)", len(stats), len(statStrings))
 	}
 
+	before := nba.GamesBefore(log, *game.GameID)
+	averages := nba.SeasonAverages(before)
+	highs := nba.SeasonHighs(before)
+	highStats := []float64{highs.PTS, highs.REB, highs.AST}
+	averageStats := []float64{averages.PTS, averages.REB, averages.AST}
+	// smallest difference from the season average worth mentioning
+	thresholds := []float64{8, 4, 4}
+
 	for i := range stats {
 		appendAndPluralize(stats[i], statStrings[i], &statline)
+		if i >= len(highStats) || len(before) == 0 || stats[i] == 0 {
+			continue
+		}
+		notes := []string{}
+		if stats[i] > highStats[i] {
+			notes = append(notes, "season-high")
+		}
+		if diff := stats[i] - averageStats[i]; diff >= thresholds[i] {
+			notes = append(notes, fmt.Sprintf("+%d over season average", int(math.Round(diff))))
+		}
+		if len(notes) > 0 {
+			statline[len(statline)-1] += " (" + strings.Join(notes, ", ") + ")"
+		}
 	}
 	if game.FGA != nil && *game.FGA > 0 {
 		fg := fmt.Sprintf("%d-%d FG (%s)", int(*game.FGM), int(*game.FGA), floatPercentage(*game.FG_PCT))
@@ -306,6 +515,126 @@ func statString(game nba.LeagueGameFinderGame) (string, error) {
 	return strings.Join(statline, ", "), nil
 }
 
+// syncRoster stores the roster of the team for the season the game was played
+// in, so reels can show player bios.
+func syncRoster(teamID int, game nba.LeagueGameFinderGame) error {
+	gameLeague := nba.LeagueFromGameID(*game.GameID)
+	season, err := nba.SeasonFromID(gameLeague, *game.SeasonID)
+	if err != nil {
+		return err
+	}
+	roster, err := nba.CommonTeamRoster(gameLeague, teamID, season)
+	if err != nil {
+		return err
+	}
+	return store.UpsertRoster(gameLeague, roster)
+}
+
+// playerBio summarizes a player's bio on one line, e.g.
+// "#11 | G | 6-2, 190 lbs | Villanova | 2018 Draft: Round 2, Pick 33".
+// Players we have no bio for are looked up with commonplayerinfo first.
+func playerBio(league nba.League, playerID int) (string, error) {
+	player, err := store.Player(playerID)
+	if err != nil || player.Height == "" {
+		info, err := nba.CommonPlayerInfo(league, playerID)
+		if err != nil {
+			return "", err
+		}
+		if err := store.UpsertPlayerInfo(league, info); err != nil {
+			return "", err
+		}
+		if player, err = store.Player(playerID); err != nil {
+			return "", err
+		}
+	}
+
+	bio := []string{}
+	if player.Jersey != "" {
+		bio = append(bio, "#"+player.Jersey)
+	}
+	if player.Position != "" {
+		bio = append(bio, player.Position)
+	}
+	if player.Height != "" && player.Weight != "" {
+		bio = append(bio, fmt.Sprintf("%s, %s lbs", player.Height, player.Weight))
+	}
+	if player.School != "" {
+		bio = append(bio, player.School)
+	}
+	if player.DraftYear == "Undrafted" {
+		bio = append(bio, "Undrafted")
+	} else if player.DraftYear != "" && player.DraftRound != "" && player.DraftNumber != "" {
+		bio = append(bio, fmt.Sprintf("%s Draft: Round %s, Pick %s", player.DraftYear, player.DraftRound, player.DraftNumber))
+	}
+	return strings.Join(bio, " | "), nil
+}
+
+// gameSummaries caches boxscoresummaryv2 by game id, every player in a team
+// reel shares the same one.
+var gameSummaries sync.Map
+
+func gameSummary(gameID string) (*nba.BoxScoreSummaryV2Data, error) {
+	if summary, ok := gameSummaries.Load(gameID); ok {
+		return summary.(*nba.BoxScoreSummaryV2Data), nil
+	}
+	summary, err := nba.BoxScoreSummaryV2(gameID)
+	if err != nil {
+		return nil, err
+	}
+	gameSummaries.Store(gameID, summary)
+	return summary, nil
+}
+
+// lineScoreString is the final score followed by the quarter breakdown, away
+// team first, e.g.
+//
+//	Final: BOS 104 - 112 NYK
+//	Q1 25-28 | Q2 22-30 | Q3 30-24 | Q4 27-30
+func lineScoreString(summary *nba.BoxScoreSummaryV2Data) string {
+	away, ok := summary.AwayLineScore()
+	if !ok {
+		return ""
+	}
+	home, ok := summary.HomeLineScore()
+	if !ok {
+		return ""
+	}
+	final := fmt.Sprintf("Final: %s %d - %d %s", deref(away.TeamAbbreviation), int(deref(away.PTS)), int(deref(home.PTS)), deref(home.TeamAbbreviation))
+
+	awayPeriods, homePeriods := away.Periods(), home.Periods()
+	periods := []string{}
+	for i := range min(len(awayPeriods), len(homePeriods)) {
+		name := fmt.Sprintf("Q%d", i+1)
+		if i >= 4 {
+			name = fmt.Sprintf("OT%d", i-3)
+		}
+		periods = append(periods, fmt.Sprintf("%s %d-%d", name, int(awayPeriods[i]), int(homePeriods[i])))
+	}
+	return final + "\n" + strings.Join(periods, " | ")
+}
+
+// videoDescription is the statline followed by the final score and the
+// player's bio when we can find them.
+func videoDescription(game nba.LeagueGameFinderGame, statline string) string {
+	description := statline
+	if summary, err := gameSummary(*game.GameID); err != nil {
+		progress.Println("failed to fetch game summary for", *game.GameID)
+		progress.Println(err)
+	} else if lineScore := lineScoreString(summary); lineScore != "" {
+		description += "\n\n" + lineScore
+	}
+	bio, err := playerBio(nba.LeagueFromGameID(*game.GameID), int(*game.PlayerId))
+	if err != nil {
+		progress.Println("failed to find bio for", *game.PlayerName)
+		progress.Println(err)
+		return description
+	}
+	if bio != "" {
+		description += "\n\n" + bio
+	}
+	return description
+}
+
 func title(game nba.LeagueGameFinderGame) (string, error) {
 	parsedDate, err := time.Parse("2006-01-02", *game.GameDate)
 	if err != nil {
@@ -346,27 +675,33 @@ type VideoRes struct {
 	OutputFile string
 }
 
-func Video(playerCode string, toDownloadsDir bool) (VideoRes, error) {
-	id, err := db.PlayerIDFromCode(playerCode)
-	res := VideoRes{}
+// Video makes one reel per selected game. Games that fail are reported and
+// skipped so one bad game doesn't cost the rest.
+func Video(playerCode string, selector GameSelector) ([]VideoRes, error) {
+	games, err := playerGames(playerCode, selector)
 	if err != nil {
-		return res, err
+		return nil, err
 	}
-	games, err := nba.LeagueGameFinderByPlayerID(id)
-	if err != nil {
-		return res, err
+	results := []VideoRes{}
+	failed := 0
+	for _, game := range games {
+		res, err := videoForGame(playerCode, game)
+		if err != nil {
+			fmt.Printf("failed to make a video of %s %s: %v\n", deref(game.GameDate), deref(game.Matchup), err)
+			failed++
+			continue
+		}
+		results = append(results, res)
 	}
-	res.Game = games[0]
-
-	measures := []nba.VideoDetailsAssetContextMeasure{
-		nba.VideoDetailsAssetContextMeasures.FGA,
-		nba.VideoDetailsAssetContextMeasures.REB,
-		nba.VideoDetailsAssetContextMeasures.AST,
-		nba.VideoDetailsAssetContextMeasures.STL,
-		nba.VideoDetailsAssetContextMeasures.TOV,
-		nba.VideoDetailsAssetContextMeasures.BLK,
+	if failed > 0 {
+		return results, fmt.Errorf("%d of %d videos failed", failed, len(games))
 	}
-	assets, err := getVideoAssets(res.Game, measures)
+	return results, nil
+}
+
+func videoForGame(playerCode string, game nba.LeagueGameFinderGame) (VideoRes, error) {
+	res := VideoRes{Game: game}
+	assets, err := getVideoAssets(res.Game, HighlightsRecipe.Measures)
 	if err != nil {
 		return res, err
 	}
@@ -377,25 +712,28 @@ func Video(playerCode string, toDownloadsDir bool) (VideoRes, error) {
 	if err != nil {
 		return res, err
 	}
-	if err := downloadAssets(&assets, tmpDir); err != nil {
+	defer os.RemoveAll(tmpDir)
+	if err := downloadAssets(playerCode, &assets, tmpDir); err != nil {
 		return res, err
 	}
-	outputFile, err := ffmpeg(tmpDir, len(assets))
+	outputFile, err := ffmpeg(playerCode, tmpDir, len(assets), clipsDuration(assets))
 	if err != nil {
 		return res, err
 	}
-	if toDownloadsDir {
-		timeString := fmt.Sprintf("%d", time.Now().Unix())
-		sum := md5.Sum([]byte(timeString))
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return res, err
-		}
-		downloadFile := home + "/Downloads/" + fmt.Sprintf("%x.mp4", sum)
-		os.Rename(outputFile, downloadFile)
-		outputFile = downloadFile
+	if outputFile, err = placeReel(outputFile, res.Game, HighlightsRecipe); err != nil {
+		return res, err
 	}
 	res.OutputFile = outputFile
+	statline, err := statString(res.Game, seasonGameLog(res.Game))
+	if err == nil {
+		err = writeSidecar(outputFile, res.Game, HighlightsRecipe, assets, statline)
+	}
+	if err != nil {
+		progress.Println("failed to write sidecar:", err)
+	}
+	if _, err := renderShotChart(res.Game, outputFile); err != nil {
+		progress.Println("failed to render shot chart:", err)
+	}
 	return res, nil
 }
 
@@ -404,11 +742,13 @@ func getVideoAssets(game nba.LeagueGameFinderGame, measures []nba.VideoDetailsAs
 	errChan := make(chan error, len(measures))
 	gaMu := sync.Mutex{}
 	gameAssets := []nba.VideoDetailAsset{}
+	task := progress.Start("clips "+deref(game.PlayerName), ProgressCount, int64(len(measures)))
 	for _, m := range measures {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			measureAssets, err := getVideoAssetsByMeasure(game, m)
+			task.Add(1)
 			if err != nil {
 				errChan <- err
 			}
@@ -423,21 +763,33 @@ func getVideoAssets(game nba.LeagueGameFinderGame, measures []nba.VideoDetailsAs
 
 	n := len(errChan)
 	if n != 0 {
-		fmt.Println(*game.PlayerName)
-		fmt.Printf("encountered %d errors when querying for assets\n", len(errChan))
+		task.Done(fmt.Errorf("%d errors", n))
+	} else {
+		task.Done(nil)
+	}
+	if n != 0 {
+		progress.Println(*game.PlayerName)
+		progress.Printf("encountered %d errors when querying for assets\n", len(errChan))
 	}
 	i := 0
 	for e := range errChan {
-		var input string
-		fmt.Printf("%d/%d:\n", i+1, n)
-		fmt.Println(e)
-		fmt.Println("Would you like to continue? (y/n)")
-		_, err := fmt.Scan(&input)
-		if err != nil {
-			return nil, err
-		}
-		if !regexp.MustCompile("^[yY]").Match([]byte(input)) {
-			return []nba.VideoDetailAsset{}, fmt.Errorf("user aborted: %s", e.Error())
+		progress.Printf("%d/%d:\n", i+1, n)
+		progress.Println(e)
+		switch mismatchPolicy {
+		case MismatchFail:
+			return []nba.VideoDetailAsset{}, e
+		case MismatchSkip:
+			return []nba.VideoDetailAsset{}, fmt.Errorf("%w: %v", errSkipPlayer, e)
+		case MismatchWarn:
+			warnMismatch(game, e)
+		default:
+			ok, err := confirm("Would you like to continue?")
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return []nba.VideoDetailAsset{}, fmt.Errorf("user aborted: %s", e.Error())
+			}
 		}
 		i++
 	}
@@ -449,12 +801,38 @@ func getVideoAssets(game nba.LeagueGameFinderGame, measures []nba.VideoDetailsAs
 	return gameAssets, nil
 }
 
+// clipMismatch is a measure whose clips don't add up to the box score.
+type clipMismatch struct {
+	measure  nba.VideoDetailsAssetContextMeasure
+	expected int
+	found    int
+}
+
+func (e *clipMismatch) Error() string {
+	return fmt.Sprintf("expected %d %s assets, have %d", e.expected, e.measure, e.found)
+}
+
+// warnMismatch reports a problem with a player's clips that the reel is
+// being made in spite of.
+func warnMismatch(game nba.LeagueGameFinderGame, err error) {
+	what := fmt.Sprintf("%s, %s %s (%s)", deref(game.PlayerName), deref(game.Matchup), deref(game.GameDate), deref(game.GameID))
+	var mismatch *clipMismatch
+	if errors.As(err, &mismatch) {
+		progress.Eprintf("warning: %s: expected %d %s clips, found %d, making the reel anyway\n", what, mismatch.expected, mismatch.measure, mismatch.found)
+		return
+	}
+	progress.Eprintf("warning: %s: %v, making the reel anyway\n", what, err)
+}
+
 func getVideoAssetsByMeasure(game nba.LeagueGameFinderGame, measure nba.VideoDetailsAssetContextMeasure) ([]nba.VideoDetailAsset, error) {
 	measureAssets := []nba.VideoDetailAsset{}
 	apiRes, err := nba.VideoDetailsAsset(*game.GameID, *game.PlayerId, *game.TeamID, measure)
 	if err != nil {
 		return nil, err
 	}
+	if err := store.InsertClips(apiRes); err != nil {
+		progress.Println("failed to catalog clips:", err)
+	}
 
 	// filter out assets with no URL
 	for _, a := range apiRes {
@@ -467,35 +845,35 @@ func getVideoAssetsByMeasure(game nba.LeagueGameFinderGame, measure nba.VideoDet
 	switch measure {
 	case "FGA":
 		if len(measureAssets) != int(*game.FGA) {
-			return measureAssets, fmt.Errorf("expected %d FGA assets, have %d", int(*game.FGA), len(measureAssets))
+			return measureAssets, &clipMismatch{measure, int(*game.FGA), len(measureAssets)}
 		}
 	case "REB":
 		if len(measureAssets) != int(*game.REB) {
-			return measureAssets, fmt.Errorf("expected %d REB assets, have %d", int(*game.REB), len(measureAssets))
+			return measureAssets, &clipMismatch{measure, int(*game.REB), len(measureAssets)}
 		}
 	case "AST":
 		if len(measureAssets) != int(*game.AST) {
-			return measureAssets, fmt.Errorf("expected %d AST assets, have %d", int(*game.AST), len(measureAssets))
+			return measureAssets, &clipMismatch{measure, int(*game.AST), len(measureAssets)}
 		}
 	case "STL":
 		if len(measureAssets) != int(*game.STL) {
-			return measureAssets, fmt.Errorf("expected %d STL assets, have %d", int(*game.STL), len(measureAssets))
+			return measureAssets, &clipMismatch{measure, int(*game.STL), len(measureAssets)}
 		}
 	case "TOV":
 		if len(measureAssets) != int(*game.TOV) {
-			return measureAssets, fmt.Errorf("expected %d TOV assets, have %d", int(*game.TOV), len(measureAssets))
+			return measureAssets, &clipMismatch{measure, int(*game.TOV), len(measureAssets)}
 		}
 	case "PF":
 		if len(measureAssets) != int(*game.PF) {
-			return measureAssets, fmt.Errorf("expected %d PF assets, have %d", int(*game.PF), len(measureAssets))
+			return measureAssets, &clipMismatch{measure, int(*game.PF), len(measureAssets)}
 		}
 	case "FTA":
 		if len(measureAssets) != int(*game.FTA) {
-			return measureAssets, fmt.Errorf("expected %d FTA assets, have %d", int(*game.FTA), len(measureAssets))
+			return measureAssets, &clipMismatch{measure, int(*game.FTA), len(measureAssets)}
 		}
 	case "BLK":
 		if len(measureAssets) != int(*game.BLK) {
-			return measureAssets, fmt.Errorf("expected %d BLK assets, have %d", int(*game.BLK), len(measureAssets))
+			return measureAssets, &clipMismatch{measure, int(*game.BLK), len(measureAssets)}
 		}
 	default:
 		return measureAssets, fmt.Errorf("unexpected context measure provided: \"%s\"", string(measure))
@@ -570,16 +948,23 @@ func mkdirTmp(playerName string, game *nba.LeagueGameFinderGame) (string, error)
 	return tmpDir, nil
 }
 
-func downloadAssets(assets *[]nba.VideoDetailAsset, tmpDir string) error {
+// downloadAssets downloads the clips into tmpDir, reporting the bytes
+// downloaded for name, e.g. the player.
+func downloadAssets(name string, assets *[]nba.VideoDetailAsset, tmpDir string) error {
 	wg := sync.WaitGroup{}
 	errChan := make(chan error, len(*assets))
+	task := progress.Start("download "+name, ProgressBytes, 0)
 
 	for i, asset := range *assets {
 		filename := fmt.Sprintf("%s/%06d.mp4", tmpDir, i)
+		if info, err := os.Stat(filename); err == nil && info.Size() > 0 {
+			// already downloaded by an earlier run of this job
+			continue
+		}
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			err := downloadVideoUrl(filename, asset)
+			err := downloadVideoUrl(filename, asset, task)
 			if err != nil {
 				errChan <- err
 			}
@@ -596,13 +981,15 @@ func downloadAssets(assets *[]nba.VideoDetailAsset, tmpDir string) error {
 		}
 	}
 	if len(errors) > 0 {
-		_ = os.RemoveAll(tmpDir)
-		return gigaError(errors)
+		err := gigaError(errors)
+		task.Done(err)
+		return err
 	}
+	task.Done(nil)
 	return nil
 }
 
-func downloadVideoUrl(filepath string, asset nba.VideoDetailAsset) error {
+func downloadVideoUrl(filepath string, asset nba.VideoDetailAsset, task *ProgressTask) error {
 	var url string
 	if asset.LargeUrl != nil {
 		url = *asset.LargeUrl
@@ -611,16 +998,39 @@ func downloadVideoUrl(filepath string, asset nba.VideoDetailAsset) error {
 	} else {
 		url = *asset.SmallUrl
 	}
-	if err := curlToFile(url, filepath); err != nil {
+	// download next to the final name so an interrupted download is never
+	// mistaken for a finished clip
+	partial := filepath + ".part"
+	if err := curlToFile(url, partial, task); err != nil {
+		_ = os.Remove(partial)
 		return err
 	}
-	return nil
+	return os.Rename(partial, filepath)
 }
 
-// ffmpeg is written in c and assembly language
-func ffmpeg(tmpDir string, count int) (string, error) {
-	if err := os.Symlink(config.EndScreenFile, fmt.Sprintf("%s/%06d.mp4", tmpDir, count)); err != nil {
-		_ = os.RemoveAll(tmpDir)
+// clipsDuration is how long the clips run, from the durations the clip
+// catalog gives in milliseconds.
+func clipsDuration(assets []nba.VideoDetailAsset) time.Duration {
+	total := 0.0
+	for _, a := range assets {
+		switch {
+		case a.LargeUrl != nil:
+			total += deref(a.LargeDur)
+		case a.MedUrl != nil:
+			total += deref(a.MedDur)
+		default:
+			total += deref(a.SmallDur)
+		}
+	}
+	return time.Duration(total) * time.Millisecond
+}
+
+// ffmpeg is written in c and assembly language. Its -progress output is
+// reported as a percentage of duration for name, e.g. the player.
+func ffmpeg(name string, tmpDir string, count int, duration time.Duration) (string, error) {
+	endScreen := fmt.Sprintf("%s/%06d.mp4", tmpDir, count)
+	_ = os.Remove(endScreen)
+	if err := os.Symlink(config.EndScreenFile, endScreen); err != nil {
 		return "", err
 	}
 
@@ -639,22 +1049,70 @@ func ffmpeg(tmpDir string, count int) (string, error) {
 
 	timeString := fmt.Sprintf("%d%d", time.Now().Unix(), rand.Intn(math.MaxInt64))
 	sum := md5.Sum([]byte(timeString))
-	outputFileName := os.TempDir() + fmt.Sprintf("%x", sum) + ".mp4"
+	outputFileName := filepath.Join(os.TempDir(), fmt.Sprintf("%x.mp4", sum))
 
-	args := []string{"-hide_banner", "-v", "fatal", "-f", "concat", "-safe", "0", "-vsync", "0", "-i", fmt.Sprintf("%s/files.txt", tmpDir), "-c", "copy", outputFileName}
+	args := []string{"-hide_banner", "-v", "fatal", "-nostats", "-progress", "pipe:1", "-f", "concat", "-safe", "0", "-vsync", "0", "-i", fmt.Sprintf("%s/files.txt", tmpDir), "-c", "copy", outputFileName}
 	cmd := exec.Command("ffmpeg", args...)
-	cmd.Stdin, cmd.Stderr, cmd.Stdout = os.Stdin, os.Stderr, os.Stdout
-	// fmt.Println(strings.Join(cmd.Args, " "))
+	cmd.Stdin, cmd.Stderr = os.Stdin, os.Stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", err
+	}
 
-	if err := cmd.Run(); err != nil {
-		_ = os.RemoveAll(tmpDir)
+	task := progress.Start("render "+name, ProgressPercent, 100)
+	if err := cmd.Start(); err != nil {
+		task.Done(err)
+		return "", err
+	}
+	// -progress writes key=value lines, out_time_us being how far in it is
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(scanner.Text(), "=")
+		if key != "out_time_us" || duration <= 0 {
+			continue
+		}
+		us, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		// the end screen isn't in duration, so hold off on 100% until ffmpeg exits
+		task.Set(min(99, 100*int64(time.Duration(us)*time.Microsecond)/int64(duration)))
+	}
+	if err := cmd.Wait(); err != nil {
+		task.Done(err)
 		_ = os.Remove(outputFileName)
 		return "", err
 	}
-	_ = os.RemoveAll(tmpDir)
+	task.Done(nil)
 	return outputFileName, nil
 }
 
+// shotChartBase is where a reel's shot chart is saved, next to the reel.
+func shotChartBase(outputFile string) string {
+	return strings.TrimSuffix(outputFile, ".mp4") + "_shotchart"
+}
+
+// renderShotChart saves the player's shot chart for the game next to the reel
+// and returns the path of the PNG, or "" if the player took no shots.
+func renderShotChart(game nba.LeagueGameFinderGame, outputFile string) (string, error) {
+	if game.FGA == nil || *game.FGA == 0 {
+		return "", nil
+	}
+	season, err := nba.SeasonFromID(nba.LeagueFromGameID(*game.GameID), *game.SeasonID)
+	if err != nil {
+		return "", err
+	}
+	detail, err := nba.ShotChartDetail(*game.GameID, int(*game.PlayerId), int(*game.TeamID), season)
+	if err != nil {
+		return "", err
+	}
+	title, err := title(game)
+	if err != nil {
+		return "", err
+	}
+	return shotchart.Save(shotChartBase(outputFile), shotchart.FromDetail(detail.Shots), title)
+}
+
 func gigaError(slice []error) error {
 	errBytes := []byte{}
 	for i := range slice {
@@ -666,7 +1124,8 @@ func gigaError(slice []error) error {
 
 var sem = make(chan int, 50)
 
-func curlToFile(url, filepath string) error {
+// curlToFile downloads url to filepath, counting the bytes towards task.
+func curlToFile(url, filepath string, task *ProgressTask) error {
 	sem <- 1
 	defer func() { <-sem }()
 	client := &http.Client{}
@@ -688,7 +1147,10 @@ func curlToFile(url, filepath string) error {
 	}
 	defer out.Close()
 
-	_, err = io.Copy(out, resp.Body)
+	if resp.ContentLength > 0 {
+		task.AddTotal(resp.ContentLength)
+	}
+	_, err = io.Copy(progressWriter{out, task}, resp.Body)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,377 @@
+package main
+
+import (
+	"basketball/config"
+	"basketball/db"
+	"basketball/nba"
+
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const manifestUsage = `A manifest lists the reels to make, e.g.
+
+  concurrency: 2
+  reels:
+    - player: Jalen Brunson
+      last: 3
+    - team: NYK
+      vs: BOS
+      upload: true
+    - player: Josh Hart
+      game_id: "0022400014"
+      recipe: highlights
+
+Each reel names a player or a team, optionally a game selector (game_id, date,
+vs, last) and a recipe, and whether to upload it. A summary of every reel is
+written as JSON when the run finishes.`
+
+// Manifest is a batch of reels read from a YAML file.
+type Manifest struct {
+	// Concurrency is how many reels are downloaded, rendered and uploaded at
+	// once. --concurrency overrides it and the config's concurrency is used
+	// when neither is set.
+	Concurrency int             `yaml:"concurrency"`
+	Upload      bool            `yaml:"upload"`
+	Reels       []ManifestEntry `yaml:"reels"`
+}
+
+type ManifestEntry struct {
+	Player string `yaml:"player"`
+	Team   string `yaml:"team"`
+	GameID string `yaml:"game_id"`
+	Date   string `yaml:"date"`
+	Vs     string `yaml:"vs"`
+	Last   int    `yaml:"last"`
+	Recipe string `yaml:"recipe"`
+	// Upload overrides the manifest's upload setting for this reel.
+	Upload *bool `yaml:"upload"`
+}
+
+func (e ManifestEntry) selector() GameSelector {
+	return GameSelector{GameID: e.GameID, Date: e.Date, Vs: e.Vs, Last: e.Last}
+}
+
+func (e ManifestEntry) String() string {
+	if e.Team != "" {
+		return "team " + e.Team
+	}
+	return e.Player
+}
+
+// LoadManifest reads and checks a manifest so mistakes surface before any
+// reel is started.
+func LoadManifest(path string) (Manifest, error) {
+	m := Manifest{}
+	f, err := os.Open(path)
+	if err != nil {
+		return m, err
+	}
+	defer f.Close()
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil {
+		return m, fmt.Errorf("%s: %v", path, err)
+	}
+	if len(m.Reels) == 0 {
+		return m, fmt.Errorf("%s: no reels", path)
+	}
+	for i := range m.Reels {
+		e := &m.Reels[i]
+		if (e.Player == "") == (e.Team == "") {
+			return m, fmt.Errorf("%s: reel %d needs exactly one of player or team", path, i+1)
+		}
+		if e.Recipe == "" {
+			e.Recipe = HighlightsRecipe.Name
+		}
+		if _, ok := Recipes[e.Recipe]; !ok {
+			return m, fmt.Errorf("%s: reel %d has unknown recipe %q", path, i+1, e.Recipe)
+		}
+		if err := e.selector().Validate(); err != nil {
+			return m, fmt.Errorf("%s: reel %d: %v", path, i+1, err)
+		}
+	}
+	return m, nil
+}
+
+// ManifestSummary is written once a manifest run finishes.
+type ManifestSummary struct {
+	Manifest  string           `json:"manifest"`
+	Started   time.Time        `json:"started"`
+	Finished  time.Time        `json:"finished"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Skipped   int              `json:"skipped"`
+	Reels     []ManifestResult `json:"reels"`
+}
+
+const (
+	manifestStatusDone    = "done"
+	manifestStatusFailed  = "failed"
+	manifestStatusSkipped = "skipped"
+)
+
+// ManifestResult is one reel of a manifest run. Reels whose player, team or
+// games couldn't be found have no job.
+type ManifestResult struct {
+	Reel       int    `json:"reel"`
+	Entry      string `json:"entry"`
+	JobID      int64  `json:"job_id,omitempty"`
+	Player     string `json:"player,omitempty"`
+	GameID     string `json:"game_id,omitempty"`
+	Recipe     string `json:"recipe,omitempty"`
+	Status     string `json:"status"`
+	Stage      string `json:"stage,omitempty"`
+	OutputFile string `json:"output_file,omitempty"`
+	VideoID    string `json:"video_id,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+type manifestJob struct {
+	reel   int
+	entry  string
+	result *ManifestResult
+	job    *db.Job
+	stop   db.JobStage
+	// same is the index in queued of an earlier reel with the same job, which
+	// runs it for both, or -1
+	same int
+}
+
+// RunManifest queues a job for every game of every reel in the manifest and
+// runs them through the job pipeline. Finding clips can prompt, so it happens
+// one job at a time; downloading, rendering and uploading are spread over
+// concurrency workers.
+func RunManifest(path string, concurrency int, summaryPath string) error {
+	m, err := LoadManifest(path)
+	if err != nil {
+		return &exitError{ExitUsage, err}
+	}
+	if concurrency <= 0 {
+		concurrency = m.Concurrency
+	}
+	if concurrency <= 0 {
+		concurrency = config.Concurrency
+	}
+
+	summary := ManifestSummary{Manifest: path, Started: time.Now()}
+	unresolved := []ManifestResult{}
+	queued := []manifestJob{}
+	byJobID := map[int64]int{}
+	for i, e := range m.Reels {
+		fmt.Printf("reel %d: %s\n", i+1, e)
+		upload := m.Upload
+		if e.Upload != nil {
+			upload = *e.Upload
+		}
+		stop := db.JobStageUpload
+		if upload && !noUpload {
+			stop = db.JobStageDone
+		}
+
+		jobs, err := manifestJobs(e)
+		if err != nil {
+			fmt.Printf("reel %d: %v\n", i+1, err)
+			unresolved = append(unresolved, ManifestResult{
+				Reel:   i + 1,
+				Entry:  e.String(),
+				Recipe: e.Recipe,
+				Status: manifestStatusFailed,
+				Error:  err.Error(),
+			})
+			continue
+		}
+		for _, job := range jobs {
+			q := manifestJob{reel: i + 1, entry: e.String(), job: job, stop: stop, same: -1}
+			// overlapping reels get the same job back from EnsureJob, and two
+			// workers running one job would fight over its files and upload
+			if j, ok := byJobID[job.ID]; ok {
+				q.same = j
+				if stop == db.JobStageDone {
+					queued[j].stop = stop
+				}
+			} else {
+				byJobID[job.ID] = len(queued)
+			}
+			queued = append(queued, q)
+		}
+	}
+	summary.Reels = make([]ManifestResult, len(queued))
+	for i := range queued {
+		queued[i].result = &summary.Reels[i]
+	}
+
+	fmt.Println("querying for asset urls...")
+	for _, q := range queued {
+		if q.same >= 0 || q.job.Status == db.JobStatusCancelled {
+			continue
+		}
+		if err := runJob(q.job, db.JobStageDownload); err != nil {
+			fmt.Println(q.job.PlayerName, err)
+		}
+	}
+
+	fmt.Printf("making %d reels, %d at a time...\n", len(byJobID), concurrency)
+	work := make(chan manifestJob)
+	wg := sync.WaitGroup{}
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for q := range work {
+				err := runManifestJob(q)
+				if err != nil {
+					progress.Printf("job %d (%s): %v\n", q.job.ID, q.job.PlayerName, err)
+				} else {
+					progress.Printf("job %d (%s) is %s\n", q.job.ID, q.job.PlayerName, q.result.Status)
+				}
+			}
+		}()
+	}
+	for _, q := range queued {
+		if q.same < 0 {
+			work <- q
+		}
+	}
+	close(work)
+	wg.Wait()
+	for _, q := range queued {
+		if q.same >= 0 {
+			*q.result = *queued[q.same].result
+			q.result.Reel = q.reel
+			q.result.Entry = q.entry
+		}
+	}
+
+	summary.Reels = append(summary.Reels, unresolved...)
+	sort.SliceStable(summary.Reels, func(i, j int) bool {
+		return summary.Reels[i].Reel < summary.Reels[j].Reel
+	})
+	summary.Finished = time.Now()
+	for _, r := range summary.Reels {
+		switch r.Status {
+		case manifestStatusDone:
+			summary.Succeeded++
+		case manifestStatusSkipped:
+			summary.Skipped++
+		default:
+			summary.Failed++
+		}
+	}
+	if summaryPath == "" {
+		summaryPath = strings.TrimSuffix(path, filepath.Ext(path)) + ".summary.json"
+	}
+	if err := writeManifestSummary(summaryPath, summary); err != nil {
+		return err
+	}
+	fmt.Printf("%d done, %d failed, %d skipped, summary written to %s\n", summary.Succeeded, summary.Failed, summary.Skipped, summaryPath)
+	if summary.Failed > 0 {
+		return fmt.Errorf("%d of %d reels failed", summary.Failed, len(summary.Reels))
+	}
+	return nil
+}
+
+// manifestJobs finds the reel's games and queues a job per player per game.
+func manifestJobs(e ManifestEntry) ([]*db.Job, error) {
+	recipe := Recipes[e.Recipe]
+	if e.Team != "" {
+		team, err := resolveTeam(e.Team)
+		if err != nil {
+			return nil, err
+		}
+		games := nba.LeagueGameFinderByTeamID(league, team.ID, e.selector().season(league))
+		recordGames(games)
+		selected, err := e.selector().Select(games)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", team.Name, err)
+		}
+		jobs := []*db.Job{}
+		finder := map[int][]nba.LeagueGameFinderGame{}
+		for _, game := range selected {
+			gameJobs, _, err := queueTeamGame(team.ID, game, recipe, finder)
+			if err != nil {
+				return nil, err
+			}
+			jobs = append(jobs, gameJobs...)
+		}
+		return jobs, nil
+	}
+
+	games, err := playerGames(e.Player, e.selector())
+	if err != nil {
+		return nil, err
+	}
+	jobs := []*db.Job{}
+	for _, game := range games {
+		job, err := queueJob(e.Player, game, recipe)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, &job)
+	}
+	return jobs, nil
+}
+
+// runManifestJob runs a queued job up to its stop stage and fills in its
+// result. The video id comes from the upload ledger, which also covers reels
+// uploaded by an earlier run.
+func runManifestJob(q manifestJob) error {
+	r, job := q.result, q.job
+	r.Reel = q.reel
+	r.Entry = q.entry
+	r.JobID = job.ID
+	r.Player = job.PlayerName
+	r.GameID = job.GameID
+	r.Recipe = job.Recipe
+
+	var err error
+	switch job.Status {
+	case db.JobStatusCancelled:
+		r.Status = manifestStatusSkipped
+	case db.JobStatusFailed:
+		// a fetch that failed above is reported rather than retried
+		if job.Stage == db.JobStageFetch {
+			err = fmt.Errorf("%s", job.Error)
+			break
+		}
+		fallthrough
+	default:
+		if err = runJob(job, q.stop); errors.Is(err, errSkipPlayer) {
+			r.Status = manifestStatusSkipped
+		}
+	}
+
+	r.Stage = string(job.Stage)
+	r.OutputFile = job.OutputFile
+	if upload, found, uploadErr := store.Upload(job.PlayerID, job.GameID, job.Recipe); uploadErr == nil && found {
+		r.VideoID = upload.VideoID
+	}
+	if err != nil {
+		if r.Status == "" {
+			r.Status = manifestStatusFailed
+		}
+		r.Error = err.Error()
+		return err
+	}
+	if r.Status == "" {
+		r.Status = manifestStatusDone
+	}
+	return nil
+}
+
+func writeManifestSummary(path string, summary ManifestSummary) error {
+	b, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0644)
+}
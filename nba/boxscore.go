@@ -0,0 +1,247 @@
+package nba
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// The V3 box score endpoints all share one shape: a game with a home and an
+// away team, each with a list of players. Only the statistics differ, so the
+// shape is generic over the statistics type.
+
+type BoxScoreV3Data[S any] struct {
+	GameId     *string           `json:"gameId"`
+	AwayTeamId *float64          `json:"awayTeamId"`
+	HomeTeamId *float64          `json:"homeTeamId"`
+	HomeTeam   BoxScoreV3Team[S] `json:"homeTeam"`
+	AwayTeam   BoxScoreV3Team[S] `json:"awayTeam"`
+}
+
+type BoxScoreV3Team[S any] struct {
+	TeamId      *float64              `json:"teamId"`
+	TeamCity    *string               `json:"teamCity"`
+	TeamName    *string               `json:"teamName"`
+	TeamTricode *string               `json:"teamTricode"`
+	TeamSlug    *string               `json:"teamSlug"`
+	Players     []BoxScoreV3Player[S] `json:"players"`
+	Statistics  S                     `json:"statistics"`
+}
+
+type BoxScoreV3Player[S any] struct {
+	PersonId   *float64 `json:"personId"`
+	FirstName  *string  `json:"firstName"`
+	FamilyName *string  `json:"familyName"`
+	NameI      *string  `json:"nameI"`
+	PlayerSlug *string  `json:"playerSlug"`
+	Position   *string  `json:"position"`
+	Comment    *string  `json:"comment"`
+	JerseyNum  *string  `json:"jerseyNum"`
+	Statistics S        `json:"statistics"`
+}
+
+// Team returns the side of the box score belonging to teamID.
+func (d *BoxScoreV3Data[S]) Team(teamID int) (BoxScoreV3Team[S], error) {
+	if d.HomeTeamId != nil && int(*d.HomeTeamId) == teamID {
+		return d.HomeTeam, nil
+	}
+	if d.AwayTeamId != nil && int(*d.AwayTeamId) == teamID {
+		return d.AwayTeam, nil
+	}
+	return BoxScoreV3Team[S]{}, fmt.Errorf("team %d did not play in game %v", teamID, d.GameId)
+}
+
+// Player finds a player's line on either team.
+func (d *BoxScoreV3Data[S]) Player(playerID int) (BoxScoreV3Player[S], bool) {
+	for _, team := range []BoxScoreV3Team[S]{d.HomeTeam, d.AwayTeam} {
+		for _, p := range team.Players {
+			if p.PersonId != nil && int(*p.PersonId) == playerID {
+				return p, true
+			}
+		}
+	}
+	return BoxScoreV3Player[S]{}, false
+}
+
+type BoxScoreAdvancedV3Stats struct {
+	Minutes                      *string  `json:"minutes"`
+	EstimatedOffensiveRating     *float64 `json:"estimatedOffensiveRating"`
+	OffensiveRating              *float64 `json:"offensiveRating"`
+	EstimatedDefensiveRating     *float64 `json:"estimatedDefensiveRating"`
+	DefensiveRating              *float64 `json:"defensiveRating"`
+	EstimatedNetRating           *float64 `json:"estimatedNetRating"`
+	NetRating                    *float64 `json:"netRating"`
+	AssistPercentage             *float64 `json:"assistPercentage"`
+	AssistToTurnover             *float64 `json:"assistToTurnover"`
+	AssistRatio                  *float64 `json:"assistRatio"`
+	OffensiveReboundPercentage   *float64 `json:"offensiveReboundPercentage"`
+	DefensiveReboundPercentage   *float64 `json:"defensiveReboundPercentage"`
+	ReboundPercentage            *float64 `json:"reboundPercentage"`
+	TurnoverRatio                *float64 `json:"turnoverRatio"`
+	EffectiveFieldGoalPercentage *float64 `json:"effectiveFieldGoalPercentage"`
+	TrueShootingPercentage       *float64 `json:"trueShootingPercentage"`
+	UsagePercentage              *float64 `json:"usagePercentage"`
+	EstimatedUsagePercentage     *float64 `json:"estimatedUsagePercentage"`
+	EstimatedPace                *float64 `json:"estimatedPace"`
+	Pace                         *float64 `json:"pace"`
+	PacePer40                    *float64 `json:"pacePer40"`
+	Possessions                  *float64 `json:"possessions"`
+	PIE                          *float64 `json:"PIE"`
+}
+
+type BoxScoreMiscV3Stats struct {
+	Minutes               *string  `json:"minutes"`
+	PointsOffTurnovers    *float64 `json:"pointsOffTurnovers"`
+	PointsSecondChance    *float64 `json:"pointsSecondChance"`
+	PointsFastBreak       *float64 `json:"pointsFastBreak"`
+	PointsPaint           *float64 `json:"pointsPaint"`
+	OppPointsOffTurnovers *float64 `json:"oppPointsOffTurnovers"`
+	OppPointsSecondChance *float64 `json:"oppPointsSecondChance"`
+	OppPointsFastBreak    *float64 `json:"oppPointsFastBreak"`
+	OppPointsPaint        *float64 `json:"oppPointsPaint"`
+	Blocks                *float64 `json:"blocks"`
+	BlocksAgainst         *float64 `json:"blocksAgainst"`
+	FoulsPersonal         *float64 `json:"foulsPersonal"`
+	FoulsDrawn            *float64 `json:"foulsDrawn"`
+}
+
+type BoxScoreScoringV3Stats struct {
+	Minutes                          *string  `json:"minutes"`
+	PercentageFieldGoalsAttempted2pt *float64 `json:"percentageFieldGoalsAttempted2pt"`
+	PercentageFieldGoalsAttempted3pt *float64 `json:"percentageFieldGoalsAttempted3pt"`
+	PercentagePoints2pt              *float64 `json:"percentagePoints2pt"`
+	PercentagePointsMidrange2pt      *float64 `json:"percentagePointsMidrange2pt"`
+	PercentagePoints3pt              *float64 `json:"percentagePoints3pt"`
+	PercentagePointsFastBreak        *float64 `json:"percentagePointsFastBreak"`
+	PercentagePointsFreeThrow        *float64 `json:"percentagePointsFreeThrow"`
+	PercentagePointsOffTurnovers     *float64 `json:"percentagePointsOffTurnovers"`
+	PercentagePointsPaint            *float64 `json:"percentagePointsPaint"`
+	PercentageAssisted2pt            *float64 `json:"percentageAssisted2pt"`
+	PercentageUnassisted2pt          *float64 `json:"percentageUnassisted2pt"`
+	PercentageAssisted3pt            *float64 `json:"percentageAssisted3pt"`
+	PercentageUnassisted3pt          *float64 `json:"percentageUnassisted3pt"`
+	PercentageAssistedFGM            *float64 `json:"percentageAssistedFGM"`
+	PercentageUnassistedFGM          *float64 `json:"percentageUnassistedFGM"`
+}
+
+type BoxScoreUsageV3Stats struct {
+	Minutes                          *string  `json:"minutes"`
+	UsagePercentage                  *float64 `json:"usagePercentage"`
+	PercentageFieldGoalsMade         *float64 `json:"percentageFieldGoalsMade"`
+	PercentageFieldGoalsAttempted    *float64 `json:"percentageFieldGoalsAttempted"`
+	PercentageThreePointersMade      *float64 `json:"percentageThreePointersMade"`
+	PercentageThreePointersAttempted *float64 `json:"percentageThreePointersAttempted"`
+	PercentageFreeThrowsMade         *float64 `json:"percentageFreeThrowsMade"`
+	PercentageFreeThrowsAttempted    *float64 `json:"percentageFreeThrowsAttempted"`
+	PercentageReboundsOffensive      *float64 `json:"percentageReboundsOffensive"`
+	PercentageReboundsDefensive      *float64 `json:"percentageReboundsDefensive"`
+	PercentageReboundsTotal          *float64 `json:"percentageReboundsTotal"`
+	PercentageAssists                *float64 `json:"percentageAssists"`
+	PercentageTurnovers              *float64 `json:"percentageTurnovers"`
+	PercentageSteals                 *float64 `json:"percentageSteals"`
+	PercentageBlocks                 *float64 `json:"percentageBlocks"`
+	PercentageBlocksAllowed          *float64 `json:"percentageBlocksAllowed"`
+	PercentagePersonalFouls          *float64 `json:"percentagePersonalFouls"`
+	PercentagePersonalFoulsDrawn     *float64 `json:"percentagePersonalFoulsDrawn"`
+	PercentagePoints                 *float64 `json:"percentagePoints"`
+}
+
+type BoxScoreFourFactorsV3Stats struct {
+	Minutes                         *string  `json:"minutes"`
+	EffectiveFieldGoalPercentage    *float64 `json:"effectiveFieldGoalPercentage"`
+	FreeThrowAttemptRate            *float64 `json:"freeThrowAttemptRate"`
+	TeamTurnoverPercentage          *float64 `json:"teamTurnoverPercentage"`
+	OffensiveReboundPercentage      *float64 `json:"offensiveReboundPercentage"`
+	OppEffectiveFieldGoalPercentage *float64 `json:"oppEffectiveFieldGoalPercentage"`
+	OppFreeThrowAttemptRate         *float64 `json:"oppFreeThrowAttemptRate"`
+	OppTeamTurnoverPercentage       *float64 `json:"oppTeamTurnoverPercentage"`
+	OppOffensiveReboundPercentage   *float64 `json:"oppOffensiveReboundPercentage"`
+}
+
+type BoxScoreHustleV2Stats struct {
+	Minutes                      *string  `json:"minutes"`
+	Points                       *float64 `json:"points"`
+	ContestedShots               *float64 `json:"contestedShots"`
+	ContestedShots2pt            *float64 `json:"contestedShots2pt"`
+	ContestedShots3pt            *float64 `json:"contestedShots3pt"`
+	Deflections                  *float64 `json:"deflections"`
+	ChargesDrawn                 *float64 `json:"chargesDrawn"`
+	ScreenAssists                *float64 `json:"screenAssists"`
+	ScreenAssistPoints           *float64 `json:"screenAssistPoints"`
+	LooseBallsRecoveredOffensive *float64 `json:"looseBallsRecoveredOffensive"`
+	LooseBallsRecoveredDefensive *float64 `json:"looseBallsRecoveredDefensive"`
+	LooseBallsRecoveredTotal     *float64 `json:"looseBallsRecoveredTotal"`
+	OffensiveBoxOuts             *float64 `json:"offensiveBoxOuts"`
+	DefensiveBoxOuts             *float64 `json:"defensiveBoxOuts"`
+	BoxOutPlayerTeamRebounds     *float64 `json:"boxOutPlayerTeamRebounds"`
+	BoxOutPlayerRebounds         *float64 `json:"boxOutPlayerRebounds"`
+	BoxOuts                      *float64 `json:"boxOuts"`
+}
+
+type (
+	BoxScoreAdvancedV3Data    = BoxScoreV3Data[BoxScoreAdvancedV3Stats]
+	BoxScoreMiscV3Data        = BoxScoreV3Data[BoxScoreMiscV3Stats]
+	BoxScoreScoringV3Data     = BoxScoreV3Data[BoxScoreScoringV3Stats]
+	BoxScoreUsageV3Data       = BoxScoreV3Data[BoxScoreUsageV3Stats]
+	BoxScoreFourFactorsV3Data = BoxScoreV3Data[BoxScoreFourFactorsV3Stats]
+	BoxScoreHustleV2Data      = BoxScoreV3Data[BoxScoreHustleV2Stats]
+)
+
+func BoxScoreAdvancedV3(gameID string) (*BoxScoreAdvancedV3Data, error) {
+	return boxScoreV3[BoxScoreAdvancedV3Stats]("boxscoreadvancedv3", "boxScoreAdvanced", gameID)
+}
+
+func BoxScoreMiscV3(gameID string) (*BoxScoreMiscV3Data, error) {
+	return boxScoreV3[BoxScoreMiscV3Stats]("boxscoremiscv3", "boxScoreMisc", gameID)
+}
+
+func BoxScoreScoringV3(gameID string) (*BoxScoreScoringV3Data, error) {
+	return boxScoreV3[BoxScoreScoringV3Stats]("boxscorescoringv3", "boxScoreScoring", gameID)
+}
+
+func BoxScoreUsageV3(gameID string) (*BoxScoreUsageV3Data, error) {
+	return boxScoreV3[BoxScoreUsageV3Stats]("boxscoreusagev3", "boxScoreUsage", gameID)
+}
+
+func BoxScoreFourFactorsV3(gameID string) (*BoxScoreFourFactorsV3Data, error) {
+	return boxScoreV3[BoxScoreFourFactorsV3Stats]("boxscorefourfactorsv3", "boxScoreFourFactors", gameID)
+}
+
+// BoxScoreHustleV2 is the one hustle box score; it has no V3 but is already in
+// the V3 shape.
+func BoxScoreHustleV2(gameID string) (*BoxScoreHustleV2Data, error) {
+	return boxScoreV3[BoxScoreHustleV2Stats]("boxscorehustlev2", "boxScoreHustle", gameID)
+}
+
+// boxScoreV3 fetches a V3-shaped box score. Every endpoint nests the data under
+// its own key (boxScoreAdvanced, boxScoreMisc, ...).
+func boxScoreV3[S any](endpoint, key, gameID string) (*BoxScoreV3Data[S], error) {
+	data := BoxScoreV3Data[S]{}
+	if err := fetchV3(endpoint, key, gameID, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// fetchV3 requests a V3 game endpoint and unmarshals the object under key
+// into v.
+func fetchV3(endpoint, key, gameID string, v any) error {
+	url := fmt.Sprintf("https://stats.nba.com/stats/%s?GameID=%s&StartPeriod=0&EndPeriod=0&StartRange=0&EndRange=0&RangeType=0", endpoint, gameID)
+	req := initNBAReq(url)
+	body, err := get(req)
+	if err != nil {
+		return err
+	}
+
+	unmarshalled := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &unmarshalled); err != nil && strings.Contains(err.Error(), "invalid character '<'") {
+		return fmt.Errorf("%s: received html response, expected json", endpoint)
+	} else if err != nil {
+		return err
+	}
+	raw, ok := unmarshalled[key]
+	if !ok {
+		return fmt.Errorf("%s: response is missing %q", endpoint, key)
+	}
+	return json.Unmarshal(raw, v)
+}
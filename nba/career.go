@@ -0,0 +1,378 @@
+package nba
+
+import (
+	"fmt"
+	"net/url"
+)
+
+type PlayerCareerSeason struct {
+	PlayerID         *float64
+	SeasonID         *string
+	LeagueID         *string
+	TeamID           *float64
+	TeamAbbreviation *string
+	PlayerAge        *float64
+	GP               *float64
+	GS               *float64
+	MIN              *float64
+	FGM              *float64
+	FGA              *float64
+	FG_PCT           *float64
+	FG3M             *float64
+	FG3A             *float64
+	FG3_PCT          *float64
+	FTM              *float64
+	FTA              *float64
+	FT_PCT           *float64
+	OREB             *float64
+	DREB             *float64
+	REB              *float64
+	AST              *float64
+	STL              *float64
+	BLK              *float64
+	TOV              *float64
+	PF               *float64
+	PTS              *float64
+}
+
+type PlayerCareerTotals struct {
+	PlayerID *float64
+	LeagueID *string
+	TeamID   *float64
+	GP       *float64
+	GS       *float64
+	MIN      *float64
+	FGM      *float64
+	FGA      *float64
+	FG_PCT   *float64
+	FG3M     *float64
+	FG3A     *float64
+	FG3_PCT  *float64
+	FTM      *float64
+	FTA      *float64
+	FT_PCT   *float64
+	OREB     *float64
+	DREB     *float64
+	REB      *float64
+	AST      *float64
+	STL      *float64
+	BLK      *float64
+	TOV      *float64
+	PF       *float64
+	PTS      *float64
+}
+
+type PlayerCareerStatsData struct {
+	RegularSeason       []PlayerCareerSeason
+	CareerRegularSeason []PlayerCareerTotals
+	PostSeason          []PlayerCareerSeason
+	CareerPostSeason    []PlayerCareerTotals
+}
+
+// PlayerCareerStats returns a player's season by season and career stats.
+// perMode is "Totals", "PerGame" or "Per36".
+func PlayerCareerStats(league League, playerID int, perMode string) (PlayerCareerStatsData, error) {
+	params := url.Values{}
+	params.Set("LeagueID", string(league))
+	params.Set("PerMode", perMode)
+	params.Set("PlayerID", fmt.Sprintf("%d", playerID))
+	sets, err := fetchResultSets("playercareerstats", "https://stats.nba.com/stats/playercareerstats?"+params.Encode())
+	if err != nil {
+		return PlayerCareerStatsData{}, err
+	}
+
+	seasonHeaders := []string{
+		"PLAYER_ID",
+		"SEASON_ID",
+		"LEAGUE_ID",
+		"TEAM_ID",
+		"TEAM_ABBREVIATION",
+		"PLAYER_AGE",
+		"GP",
+		"GS",
+		"MIN",
+		"FGM",
+		"FGA",
+		"FG_PCT",
+		"FG3M",
+		"FG3A",
+		"FG3_PCT",
+		"FTM",
+		"FTA",
+		"FT_PCT",
+		"OREB",
+		"DREB",
+		"REB",
+		"AST",
+		"STL",
+		"BLK",
+		"TOV",
+		"PF",
+		"PTS",
+	}
+	careerHeaders := []string{
+		"PLAYER_ID",
+		"LEAGUE_ID",
+		"TEAM_ID",
+		"GP",
+		"GS",
+		"MIN",
+		"FGM",
+		"FGA",
+		"FG_PCT",
+		"FG3M",
+		"FG3A",
+		"FG3_PCT",
+		"FTM",
+		"FTA",
+		"FT_PCT",
+		"OREB",
+		"DREB",
+		"REB",
+		"AST",
+		"STL",
+		"BLK",
+		"TOV",
+		"PF",
+		"PTS",
+	}
+
+	data := PlayerCareerStatsData{}
+	for _, s := range []struct {
+		name string
+		dest *[]PlayerCareerSeason
+	}{
+		{"SeasonTotalsRegularSeason", &data.RegularSeason},
+		{"SeasonTotalsPostSeason", &data.PostSeason},
+	} {
+		set, err := resultSet("playercareerstats", sets, s.name, seasonHeaders)
+		if err != nil {
+			return data, err
+		}
+		*s.dest = make([]PlayerCareerSeason, len(set.RowSet))
+		for i, raw := range set.RowSet {
+			(*s.dest)[i] = PlayerCareerSeason{
+				PlayerID:         maybe[float64](raw[0]),
+				SeasonID:         maybe[string](raw[1]),
+				LeagueID:         maybe[string](raw[2]),
+				TeamID:           maybe[float64](raw[3]),
+				TeamAbbreviation: maybe[string](raw[4]),
+				PlayerAge:        maybe[float64](raw[5]),
+				GP:               maybe[float64](raw[6]),
+				GS:               maybe[float64](raw[7]),
+				MIN:              maybe[float64](raw[8]),
+				FGM:              maybe[float64](raw[9]),
+				FGA:              maybe[float64](raw[10]),
+				FG_PCT:           maybe[float64](raw[11]),
+				FG3M:             maybe[float64](raw[12]),
+				FG3A:             maybe[float64](raw[13]),
+				FG3_PCT:          maybe[float64](raw[14]),
+				FTM:              maybe[float64](raw[15]),
+				FTA:              maybe[float64](raw[16]),
+				FT_PCT:           maybe[float64](raw[17]),
+				OREB:             maybe[float64](raw[18]),
+				DREB:             maybe[float64](raw[19]),
+				REB:              maybe[float64](raw[20]),
+				AST:              maybe[float64](raw[21]),
+				STL:              maybe[float64](raw[22]),
+				BLK:              maybe[float64](raw[23]),
+				TOV:              maybe[float64](raw[24]),
+				PF:               maybe[float64](raw[25]),
+				PTS:              maybe[float64](raw[26]),
+			}
+		}
+	}
+	for _, s := range []struct {
+		name string
+		dest *[]PlayerCareerTotals
+	}{
+		{"CareerTotalsRegularSeason", &data.CareerRegularSeason},
+		{"CareerTotalsPostSeason", &data.CareerPostSeason},
+	} {
+		set, err := resultSet("playercareerstats", sets, s.name, careerHeaders)
+		if err != nil {
+			return data, err
+		}
+		*s.dest = make([]PlayerCareerTotals, len(set.RowSet))
+		for i, raw := range set.RowSet {
+			(*s.dest)[i] = PlayerCareerTotals{
+				PlayerID: maybe[float64](raw[0]),
+				LeagueID: maybe[string](raw[1]),
+				TeamID:   maybe[float64](raw[2]),
+				GP:       maybe[float64](raw[3]),
+				GS:       maybe[float64](raw[4]),
+				MIN:      maybe[float64](raw[5]),
+				FGM:      maybe[float64](raw[6]),
+				FGA:      maybe[float64](raw[7]),
+				FG_PCT:   maybe[float64](raw[8]),
+				FG3M:     maybe[float64](raw[9]),
+				FG3A:     maybe[float64](raw[10]),
+				FG3_PCT:  maybe[float64](raw[11]),
+				FTM:      maybe[float64](raw[12]),
+				FTA:      maybe[float64](raw[13]),
+				FT_PCT:   maybe[float64](raw[14]),
+				OREB:     maybe[float64](raw[15]),
+				DREB:     maybe[float64](raw[16]),
+				REB:      maybe[float64](raw[17]),
+				AST:      maybe[float64](raw[18]),
+				STL:      maybe[float64](raw[19]),
+				BLK:      maybe[float64](raw[20]),
+				TOV:      maybe[float64](raw[21]),
+				PF:       maybe[float64](raw[22]),
+				PTS:      maybe[float64](raw[23]),
+			}
+		}
+	}
+	return data, nil
+}
+
+type LeagueDashPlayer struct {
+	PlayerID         *float64
+	PlayerName       *string
+	Nickname         *string
+	TeamID           *float64
+	TeamAbbreviation *string
+	Age              *float64
+	GP               *float64
+	W                *float64
+	L                *float64
+	W_PCT            *float64
+	MIN              *float64
+	FGM              *float64
+	FGA              *float64
+	FG_PCT           *float64
+	FG3M             *float64
+	FG3A             *float64
+	FG3_PCT          *float64
+	FTM              *float64
+	FTA              *float64
+	FT_PCT           *float64
+	OREB             *float64
+	DREB             *float64
+	REB              *float64
+	AST              *float64
+	TOV              *float64
+	STL              *float64
+	BLK              *float64
+	BLKA             *float64
+	PF               *float64
+	PFD              *float64
+	PTS              *float64
+	PlusMinus        *float64
+}
+
+// dashParams are the filters every dashboard endpoint requires, set to
+// "everything".
+func dashParams(league League, season, seasonType, perMode string) url.Values {
+	params := url.Values{}
+	for _, empty := range []string{
+		"College", "Conference", "Country", "DateFrom", "DateTo", "Division", "DraftPick", "DraftYear",
+		"GameScope", "GameSegment", "Height", "Location", "Outcome", "PlayerExperience", "PlayerPosition",
+		"SeasonSegment", "ShotClockRange", "StarterBench", "VsConference", "VsDivision", "Weight",
+	} {
+		params.Set(empty, "")
+	}
+	params.Set("LastNGames", "0")
+	params.Set("LeagueID", string(league))
+	params.Set("Month", "0")
+	params.Set("OpponentTeamID", "0")
+	params.Set("PORound", "0")
+	params.Set("PerMode", perMode)
+	params.Set("Period", "0")
+	params.Set("Season", season)
+	params.Set("SeasonType", seasonType)
+	params.Set("TeamID", "0")
+	return params
+}
+
+// LeagueDashPlayerStats returns every player's stats for a season.
+// perMode is "Totals", "PerGame", "Per36", etc.
+func LeagueDashPlayerStats(league League, season, seasonType, perMode string) ([]LeagueDashPlayer, error) {
+	params := dashParams(league, season, seasonType, perMode)
+	params.Set("MeasureType", "Base")
+	params.Set("PaceAdjust", "N")
+	params.Set("PlusMinus", "N")
+	params.Set("Rank", "N")
+	sets, err := fetchResultSets("leaguedashplayerstats", "https://stats.nba.com/stats/leaguedashplayerstats?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	expectedHeaders := []string{
+		"PLAYER_ID",
+		"PLAYER_NAME",
+		"NICKNAME",
+		"TEAM_ID",
+		"TEAM_ABBREVIATION",
+		"AGE",
+		"GP",
+		"W",
+		"L",
+		"W_PCT",
+		"MIN",
+		"FGM",
+		"FGA",
+		"FG_PCT",
+		"FG3M",
+		"FG3A",
+		"FG3_PCT",
+		"FTM",
+		"FTA",
+		"FT_PCT",
+		"OREB",
+		"DREB",
+		"REB",
+		"AST",
+		"TOV",
+		"STL",
+		"BLK",
+		"BLKA",
+		"PF",
+		"PFD",
+		"PTS",
+		"PLUS_MINUS",
+	}
+	set, err := resultSetWithPrefix("leaguedashplayerstats", sets, "LeagueDashPlayerStats", expectedHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	players := make([]LeagueDashPlayer, len(set.RowSet))
+	for i, raw := range set.RowSet {
+		players[i] = LeagueDashPlayer{
+			PlayerID:         maybe[float64](raw[0]),
+			PlayerName:       maybe[string](raw[1]),
+			Nickname:         maybe[string](raw[2]),
+			TeamID:           maybe[float64](raw[3]),
+			TeamAbbreviation: maybe[string](raw[4]),
+			Age:              maybe[float64](raw[5]),
+			GP:               maybe[float64](raw[6]),
+			W:                maybe[float64](raw[7]),
+			L:                maybe[float64](raw[8]),
+			W_PCT:            maybe[float64](raw[9]),
+			MIN:              maybe[float64](raw[10]),
+			FGM:              maybe[float64](raw[11]),
+			FGA:              maybe[float64](raw[12]),
+			FG_PCT:           maybe[float64](raw[13]),
+			FG3M:             maybe[float64](raw[14]),
+			FG3A:             maybe[float64](raw[15]),
+			FG3_PCT:          maybe[float64](raw[16]),
+			FTM:              maybe[float64](raw[17]),
+			FTA:              maybe[float64](raw[18]),
+			FT_PCT:           maybe[float64](raw[19]),
+			OREB:             maybe[float64](raw[20]),
+			DREB:             maybe[float64](raw[21]),
+			REB:              maybe[float64](raw[22]),
+			AST:              maybe[float64](raw[23]),
+			TOV:              maybe[float64](raw[24]),
+			STL:              maybe[float64](raw[25]),
+			BLK:              maybe[float64](raw[26]),
+			BLKA:             maybe[float64](raw[27]),
+			PF:               maybe[float64](raw[28]),
+			PFD:              maybe[float64](raw[29]),
+			PTS:              maybe[float64](raw[30]),
+			PlusMinus:        maybe[float64](raw[31]),
+		}
+	}
+	return players, nil
+}
@@ -0,0 +1,225 @@
+package nba
+
+import (
+	"fmt"
+	"net/url"
+)
+
+type PlayerGameLogGame struct {
+	SeasonID       *string
+	PlayerID       *float64
+	GameID         *string
+	GameDate       *string
+	Matchup        *string
+	WL             *string
+	MIN            *float64
+	FGM            *float64
+	FGA            *float64
+	FG_PCT         *float64
+	FG3M           *float64
+	FG3A           *float64
+	FG3_PCT        *float64
+	FTM            *float64
+	FTA            *float64
+	FT_PCT         *float64
+	OREB           *float64
+	DREB           *float64
+	REB            *float64
+	AST            *float64
+	STL            *float64
+	BLK            *float64
+	TOV            *float64
+	PF             *float64
+	PTS            *float64
+	PlusMinus      *float64
+	VideoAvailable *float64
+}
+
+// PlayerGameLog returns every game a player played in a season, most recent
+// first. seasonType is "Regular Season", "Playoffs", etc.
+func PlayerGameLog(league League, playerID int, season, seasonType string) ([]PlayerGameLogGame, error) {
+	params := url.Values{}
+	params.Set("LeagueID", string(league))
+	params.Set("PlayerID", fmt.Sprintf("%d", playerID))
+	params.Set("Season", season)
+	params.Set("SeasonType", seasonType)
+	sets, err := fetchResultSets("playergamelog", "https://stats.nba.com/stats/playergamelog?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	expectedHeaders := []string{
+		"SEASON_ID",
+		"Player_ID",
+		"Game_ID",
+		"GAME_DATE",
+		"MATCHUP",
+		"WL",
+		"MIN",
+		"FGM",
+		"FGA",
+		"FG_PCT",
+		"FG3M",
+		"FG3A",
+		"FG3_PCT",
+		"FTM",
+		"FTA",
+		"FT_PCT",
+		"OREB",
+		"DREB",
+		"REB",
+		"AST",
+		"STL",
+		"BLK",
+		"TOV",
+		"PF",
+		"PTS",
+		"PLUS_MINUS",
+		"VIDEO_AVAILABLE",
+	}
+	set, err := resultSet("playergamelog", sets, "PlayerGameLog", expectedHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	games := make([]PlayerGameLogGame, len(set.RowSet))
+	for i, raw := range set.RowSet {
+		games[i] = PlayerGameLogGame{
+			SeasonID:       maybe[string](raw[0]),
+			PlayerID:       maybe[float64](raw[1]),
+			GameID:         maybe[string](raw[2]),
+			GameDate:       maybe[string](raw[3]),
+			Matchup:        maybe[string](raw[4]),
+			WL:             maybe[string](raw[5]),
+			MIN:            maybe[float64](raw[6]),
+			FGM:            maybe[float64](raw[7]),
+			FGA:            maybe[float64](raw[8]),
+			FG_PCT:         maybe[float64](raw[9]),
+			FG3M:           maybe[float64](raw[10]),
+			FG3A:           maybe[float64](raw[11]),
+			FG3_PCT:        maybe[float64](raw[12]),
+			FTM:            maybe[float64](raw[13]),
+			FTA:            maybe[float64](raw[14]),
+			FT_PCT:         maybe[float64](raw[15]),
+			OREB:           maybe[float64](raw[16]),
+			DREB:           maybe[float64](raw[17]),
+			REB:            maybe[float64](raw[18]),
+			AST:            maybe[float64](raw[19]),
+			STL:            maybe[float64](raw[20]),
+			BLK:            maybe[float64](raw[21]),
+			TOV:            maybe[float64](raw[22]),
+			PF:             maybe[float64](raw[23]),
+			PTS:            maybe[float64](raw[24]),
+			PlusMinus:      maybe[float64](raw[25]),
+			VideoAvailable: maybe[float64](raw[26]),
+		}
+	}
+	return games, nil
+}
+
+// PlayerAverages holds per game averages (or, from SeasonHighs, the best
+// single game value) over a set of games.
+type PlayerAverages struct {
+	Games     int
+	MIN       float64
+	PTS       float64
+	REB       float64
+	OREB      float64
+	DREB      float64
+	AST       float64
+	STL       float64
+	BLK       float64
+	TOV       float64
+	PF        float64
+	FGM       float64
+	FGA       float64
+	FG3M      float64
+	FG3A      float64
+	FTM       float64
+	FTA       float64
+	PlusMinus float64
+}
+
+func value(stat *float64) float64 {
+	if stat == nil {
+		return 0
+	}
+	return *stat
+}
+
+type statPair struct {
+	game  *float64
+	total *float64
+}
+
+// stats pairs the game's counting stats with the matching PlayerAverages
+// fields so averages and highs can loop over them.
+func (a *PlayerAverages) stats(g *PlayerGameLogGame) []statPair {
+	return []statPair{
+		{g.MIN, &a.MIN},
+		{g.PTS, &a.PTS},
+		{g.REB, &a.REB},
+		{g.OREB, &a.OREB},
+		{g.DREB, &a.DREB},
+		{g.AST, &a.AST},
+		{g.STL, &a.STL},
+		{g.BLK, &a.BLK},
+		{g.TOV, &a.TOV},
+		{g.PF, &a.PF},
+		{g.FGM, &a.FGM},
+		{g.FGA, &a.FGA},
+		{g.FG3M, &a.FG3M},
+		{g.FG3A, &a.FG3A},
+		{g.FTM, &a.FTM},
+		{g.FTA, &a.FTA},
+		{g.PlusMinus, &a.PlusMinus},
+	}
+}
+
+// SeasonAverages averages every game in the log.
+func SeasonAverages(games []PlayerGameLogGame) PlayerAverages {
+	avg := PlayerAverages{Games: len(games)}
+	if len(games) == 0 {
+		return avg
+	}
+	for _, g := range games {
+		for _, s := range avg.stats(&g) {
+			*s.total += value(s.game) / float64(len(games))
+		}
+	}
+	return avg
+}
+
+// RollingAverages averages the n most recent games in the log, which is
+// ordered most recent first.
+func RollingAverages(games []PlayerGameLogGame, n int) PlayerAverages {
+	if n < len(games) {
+		games = games[:n]
+	}
+	return SeasonAverages(games)
+}
+
+// SeasonHighs is the best single game value of each stat in the log.
+// PlusMinus is the best plus-minus, not the largest magnitude.
+func SeasonHighs(games []PlayerGameLogGame) PlayerAverages {
+	highs := PlayerAverages{Games: len(games)}
+	for i, g := range games {
+		for _, s := range highs.stats(&g) {
+			if i == 0 || value(s.game) > *s.total {
+				*s.total = value(s.game)
+			}
+		}
+	}
+	return highs
+}
+
+// GamesBefore returns the games in the log that were played before gameID,
+// which is what a game should be compared against.
+func GamesBefore(games []PlayerGameLogGame, gameID string) []PlayerGameLogGame {
+	for i, g := range games {
+		if g.GameID != nil && *g.GameID == gameID {
+			return games[i+1:]
+		}
+	}
+	return games
+}
@@ -0,0 +1,113 @@
+package nba
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func logGame(gameID string, pts, reb, ast, plusMinus float64) PlayerGameLogGame {
+	return PlayerGameLogGame{GameID: ptr(gameID), PTS: ptr(pts), REB: ptr(reb), AST: ptr(ast), PlusMinus: ptr(plusMinus)}
+}
+
+// gameLog is most recent first, like the endpoint returns it.
+var gameLog = []PlayerGameLogGame{
+	logGame("0022400004", 40, 3, 9, -4),
+	logGame("0022400003", 20, 6, 3, -12),
+	logGame("0022400002", 30, 0, 6, -2),
+	logGame("0022400001", 22, 5, 6, -8),
+}
+
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestSeasonAverages(t *testing.T) {
+	missingStats := PlayerGameLogGame{GameID: ptr("0022400005"), PTS: ptr(12.0)}
+	tests := []struct {
+		name              string
+		games             []PlayerGameLogGame
+		pts, reb, ast, pm float64
+	}{
+		{"empty", nil, 0, 0, 0, 0},
+		{"one game", gameLog[:1], 40, 3, 9, -4},
+		{"season", gameLog, 28, 3.5, 6, -6.5},
+		{"missing stats count as zero", []PlayerGameLogGame{missingStats, gameLog[0]}, 26, 1.5, 4.5, -2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			avg := SeasonAverages(tt.games)
+			if avg.Games != len(tt.games) {
+				t.Errorf("got %d games, want %d", avg.Games, len(tt.games))
+			}
+			if !closeTo(avg.PTS, tt.pts) || !closeTo(avg.REB, tt.reb) || !closeTo(avg.AST, tt.ast) || !closeTo(avg.PlusMinus, tt.pm) {
+				t.Errorf("got %v/%v/%v %+v, want %v/%v/%v %+v", avg.PTS, avg.REB, avg.AST, avg.PlusMinus, tt.pts, tt.reb, tt.ast, tt.pm)
+			}
+		})
+	}
+}
+
+func TestRollingAverages(t *testing.T) {
+	tests := []struct {
+		n     int
+		games int
+		pts   float64
+	}{
+		{1, 1, 40},
+		{2, 2, 30},
+		{10, 4, 28},
+	}
+	for _, tt := range tests {
+		avg := RollingAverages(gameLog, tt.n)
+		if avg.Games != tt.games || !closeTo(avg.PTS, tt.pts) {
+			t.Errorf("RollingAverages(%d) = %d games averaging %v, want %d averaging %v", tt.n, avg.Games, avg.PTS, tt.games, tt.pts)
+		}
+	}
+}
+
+func TestSeasonHighs(t *testing.T) {
+	tests := []struct {
+		name              string
+		games             []PlayerGameLogGame
+		pts, reb, ast, pm float64
+	}{
+		{"empty", nil, 0, 0, 0, 0},
+		{"season", gameLog, 40, 6, 9, -2},
+		// the best of all negative plus-minuses is the one closest to zero
+		{"all negative", gameLog[1:2], 20, 6, 3, -12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			highs := SeasonHighs(tt.games)
+			if highs.PTS != tt.pts || highs.REB != tt.reb || highs.AST != tt.ast || highs.PlusMinus != tt.pm {
+				t.Errorf("got %v/%v/%v %+v, want %v/%v/%v %+v", highs.PTS, highs.REB, highs.AST, highs.PlusMinus, tt.pts, tt.reb, tt.ast, tt.pm)
+			}
+		})
+	}
+}
+
+func TestGamesBefore(t *testing.T) {
+	tests := []struct {
+		gameID string
+		want   []string
+	}{
+		{"0022400004", []string{"0022400003", "0022400002", "0022400001"}},
+		{"0022400002", []string{"0022400001"}},
+		{"0022400001", []string{}},
+		// a game that isn't in the log yet comes after all of it
+		{"0022400005", []string{"0022400004", "0022400003", "0022400002", "0022400001"}},
+	}
+	for _, tt := range tests {
+		got := []string{}
+		for _, g := range GamesBefore(gameLog, tt.gameID) {
+			got = append(got, *g.GameID)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("GamesBefore(%s) = %v, want %v", tt.gameID, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package nba
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// League is the LeagueID the stats API takes. Game IDs start with it too:
+// 0022400001 is an NBA game, 1022400001 a WNBA game.
+type League string
+
+const (
+	LeagueNBA     League = "00"
+	LeagueWNBA    League = "10"
+	LeagueGLeague League = "20"
+)
+
+func (l League) String() string {
+	switch l {
+	case LeagueNBA:
+		return "nba"
+	case LeagueWNBA:
+		return "wnba"
+	case LeagueGLeague:
+		return "gleague"
+	default:
+		return string(l)
+	}
+}
+
+// ParseLeague is the inverse of League.String.
+func ParseLeague(s string) (League, error) {
+	for _, l := range []League{LeagueNBA, LeagueWNBA, LeagueGLeague} {
+		if strings.EqualFold(s, l.String()) {
+			return l, nil
+		}
+	}
+	return "", fmt.Errorf("unknown league %q, expected nba, wnba or gleague", s)
+}
+
+// LeagueFromGameID reads the league out of a game ID's first two digits.
+func LeagueFromGameID(gameID string) League {
+	if len(gameID) < 2 {
+		return LeagueNBA
+	}
+	return League(gameID[:2])
+}
+
+// Season formats the season starting in year the way the league's endpoints
+// expect it. WNBA seasons fit in a calendar year ("2025"), NBA and G League
+// seasons don't ("2025-26").
+func (l League) Season(year int) string {
+	if l == LeagueWNBA {
+		return fmt.Sprintf("%d", year)
+	}
+	return fmt.Sprintf("%d-%02d", year, (year+1)%100)
+}
+
+// CurrentSeason is the season in progress on t, or the one that ended most
+// recently in the offseason.
+func (l League) CurrentSeason(t time.Time) string {
+	year := t.Year()
+	switch l {
+	case LeagueWNBA:
+		// the WNBA tips off in May
+		if t.Month() < time.May {
+			year--
+		}
+	default:
+		// the NBA and G League tip off in late October and November
+		if t.Month() < time.October {
+			year--
+		}
+	}
+	return l.Season(year)
+}
+
+// SeasonFromGameID reads the season out of a game ID: 0022400001 was played in
+// the 2024-25 season.
+func SeasonFromGameID(gameID string) (string, error) {
+	if len(gameID) < 5 {
+		return "", fmt.Errorf("unexpected game id %q", gameID)
+	}
+	var yy int
+	if _, err := fmt.Sscanf(gameID[3:5], "%d", &yy); err != nil {
+		return "", fmt.Errorf("unexpected game id %q: %v", gameID, err)
+	}
+	return LeagueFromGameID(gameID).Season(2000 + yy), nil
+}
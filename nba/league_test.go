@@ -0,0 +1,122 @@
+package nba
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLeagueFromGameID(t *testing.T) {
+	tests := []struct {
+		gameID string
+		want   League
+	}{
+		{"0022400001", LeagueNBA},
+		{"0042400101", LeagueNBA},
+		{"1022500001", LeagueWNBA},
+		{"2022400001", LeagueGLeague},
+		{"0", LeagueNBA},
+		{"", LeagueNBA},
+	}
+	for _, tt := range tests {
+		if got := LeagueFromGameID(tt.gameID); got != tt.want {
+			t.Errorf("LeagueFromGameID(%q) = %s, want %s", tt.gameID, got, tt.want)
+		}
+	}
+}
+
+func TestSeasonFromGameID(t *testing.T) {
+	tests := []struct {
+		gameID  string
+		want    string
+		wantErr bool
+	}{
+		{"0022400001", "2024-25", false},
+		{"0042400101", "2024-25", false},
+		{"0029900001", "2099-00", false},
+		{"0020000001", "2000-01", false},
+		{"1022500001", "2025", false},
+		{"2022400001", "2024-25", false},
+		{"0022", "", true},
+		{"002xx00001", "", true},
+	}
+	for _, tt := range tests {
+		got, err := SeasonFromGameID(tt.gameID)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("SeasonFromGameID(%q) = %q, %v, want %q (error %v)", tt.gameID, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestSeasonFromID(t *testing.T) {
+	tests := []struct {
+		league   League
+		seasonID string
+		want     string
+		wantErr  bool
+	}{
+		{LeagueNBA, "22024", "2024-25", false},
+		{LeagueNBA, "42019", "2019-20", false},
+		{LeagueWNBA, "22025", "2025", false},
+		{LeagueGLeague, "22024", "2024-25", false},
+		{LeagueNBA, "224", "", true},
+		{LeagueNBA, "2abcd", "", true},
+	}
+	for _, tt := range tests {
+		got, err := SeasonFromID(tt.league, tt.seasonID)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("SeasonFromID(%s, %q) = %q, %v, want %q (error %v)", tt.league, tt.seasonID, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestCurrentSeason(t *testing.T) {
+	day := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	tests := []struct {
+		league League
+		on     string
+		want   string
+	}{
+		{LeagueNBA, "2025-01-20", "2024-25"},
+		{LeagueNBA, "2025-09-30", "2024-25"},
+		{LeagueNBA, "2025-10-01", "2025-26"},
+		{LeagueGLeague, "2025-11-15", "2025-26"},
+		{LeagueWNBA, "2025-04-30", "2024"},
+		{LeagueWNBA, "2025-05-01", "2025"},
+		{LeagueWNBA, "2025-12-01", "2025"},
+	}
+	for _, tt := range tests {
+		if got := tt.league.CurrentSeason(day(tt.on)); got != tt.want {
+			t.Errorf("%s.CurrentSeason(%s) = %q, want %q", tt.league, tt.on, got, tt.want)
+		}
+	}
+}
+
+func TestParseLeague(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    League
+		wantErr bool
+	}{
+		{"nba", LeagueNBA, false},
+		{"WNBA", LeagueWNBA, false},
+		{"gleague", LeagueGLeague, false},
+		{"g-league", "", true},
+		{"00", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLeague(tt.in)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseLeague(%q) = %q, %v", tt.in, got, err)
+		}
+		if err == nil && !strings.EqualFold(got.String(), tt.in) {
+			t.Errorf("%q.String() = %q", got, got.String())
+		}
+	}
+}
@@ -0,0 +1,352 @@
+package nba
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// The liveData feeds on cdn.nba.com are static json files refreshed during
+// games. Unlike stats.nba.com they are available while a game is in progress,
+// but only for the current day's games.
+
+const liveDataURL = "https://cdn.nba.com/static/json/liveData"
+
+type LiveScoreboardData struct {
+	GameDate   *string    `json:"gameDate"`
+	LeagueId   *string    `json:"leagueId"`
+	LeagueName *string    `json:"leagueName"`
+	Games      []LiveGame `json:"games"`
+}
+
+type LiveGame struct {
+	GameId            *string  `json:"gameId"`
+	GameCode          *string  `json:"gameCode"`
+	GameStatus        *float64 `json:"gameStatus"`
+	GameStatusText    *string  `json:"gameStatusText"`
+	Period            *float64 `json:"period"`
+	GameClock         *string  `json:"gameClock"`
+	GameTimeUTC       *string  `json:"gameTimeUTC"`
+	GameEt            *string  `json:"gameEt"`
+	RegulationPeriods *float64 `json:"regulationPeriods"`
+	SeriesText        *string  `json:"seriesText"`
+	HomeTeam          LiveTeam `json:"homeTeam"`
+	AwayTeam          LiveTeam `json:"awayTeam"`
+}
+
+func (g *LiveGame) Status() GameStatus {
+	return gameStatus(g.GameStatus)
+}
+
+// HasTeam reports whether the team with the given tricode is playing.
+func (g *LiveGame) HasTeam(tricode string) bool {
+	return hasTricode(g.HomeTeam.TeamTricode, tricode) || hasTricode(g.AwayTeam.TeamTricode, tricode)
+}
+
+type LiveTeam struct {
+	TeamId            *float64       `json:"teamId"`
+	TeamName          *string        `json:"teamName"`
+	TeamCity          *string        `json:"teamCity"`
+	TeamTricode       *string        `json:"teamTricode"`
+	Wins              *float64       `json:"wins"`
+	Losses            *float64       `json:"losses"`
+	Score             *float64       `json:"score"`
+	InBonus           *string        `json:"inBonus"`
+	TimeoutsRemaining *float64       `json:"timeoutsRemaining"`
+	Periods           []LivePeriod   `json:"periods"`
+	Players           []LivePlayer   `json:"players"`
+	Statistics        *LiveTeamStats `json:"statistics"`
+}
+
+type LivePeriod struct {
+	Period     *float64 `json:"period"`
+	PeriodType *string  `json:"periodType"`
+	Score      *float64 `json:"score"`
+}
+
+type LivePlayer struct {
+	Status     *string         `json:"status"`
+	Order      *float64        `json:"order"`
+	PersonId   *float64        `json:"personId"`
+	JerseyNum  *string         `json:"jerseyNum"`
+	Position   *string         `json:"position"`
+	Starter    *string         `json:"starter"`
+	Oncourt    *string         `json:"oncourt"`
+	Played     *string         `json:"played"`
+	Name       *string         `json:"name"`
+	NameI      *string         `json:"nameI"`
+	FirstName  *string         `json:"firstName"`
+	FamilyName *string         `json:"familyName"`
+	Statistics LivePlayerStats `json:"statistics"`
+}
+
+type LivePlayerStats struct {
+	Assists                 *float64 `json:"assists"`
+	Blocks                  *float64 `json:"blocks"`
+	FieldGoalsAttempted     *float64 `json:"fieldGoalsAttempted"`
+	FieldGoalsMade          *float64 `json:"fieldGoalsMade"`
+	FieldGoalsPercentage    *float64 `json:"fieldGoalsPercentage"`
+	FoulsPersonal           *float64 `json:"foulsPersonal"`
+	FreeThrowsAttempted     *float64 `json:"freeThrowsAttempted"`
+	FreeThrowsMade          *float64 `json:"freeThrowsMade"`
+	FreeThrowsPercentage    *float64 `json:"freeThrowsPercentage"`
+	Minutes                 *string  `json:"minutes"`
+	Plus                    *float64 `json:"plus"`
+	Minus                   *float64 `json:"minus"`
+	PlusMinusPoints         *float64 `json:"plusMinusPoints"`
+	Points                  *float64 `json:"points"`
+	ReboundsDefensive       *float64 `json:"reboundsDefensive"`
+	ReboundsOffensive       *float64 `json:"reboundsOffensive"`
+	ReboundsTotal           *float64 `json:"reboundsTotal"`
+	Steals                  *float64 `json:"steals"`
+	ThreePointersAttempted  *float64 `json:"threePointersAttempted"`
+	ThreePointersMade       *float64 `json:"threePointersMade"`
+	ThreePointersPercentage *float64 `json:"threePointersPercentage"`
+	Turnovers               *float64 `json:"turnovers"`
+}
+
+type LiveTeamStats struct {
+	LivePlayerStats
+	BiggestLead         *float64 `json:"biggestLead"`
+	LeadChanges         *float64 `json:"leadChanges"`
+	TimesTied           *float64 `json:"timesTied"`
+	PointsFastBreak     *float64 `json:"pointsFastBreak"`
+	PointsInThePaint    *float64 `json:"pointsInThePaint"`
+	PointsSecondChance  *float64 `json:"pointsSecondChance"`
+	PointsFromTurnovers *float64 `json:"pointsFromTurnovers"`
+}
+
+type LiveBoxScoreData struct {
+	LiveGame
+	Duration   *float64 `json:"duration"`
+	Attendance *float64 `json:"attendance"`
+	Sellout    *string  `json:"sellout"`
+	Arena      struct {
+		ArenaId       *float64 `json:"arenaId"`
+		ArenaName     *string  `json:"arenaName"`
+		ArenaCity     *string  `json:"arenaCity"`
+		ArenaState    *string  `json:"arenaState"`
+		ArenaCountry  *string  `json:"arenaCountry"`
+		ArenaTimezone *string  `json:"arenaTimezone"`
+	} `json:"arena"`
+}
+
+type LivePlayByPlayAction struct {
+	ActionNumber *float64 `json:"actionNumber"`
+	Clock        *string  `json:"clock"`
+	TimeActual   *string  `json:"timeActual"`
+	Period       *float64 `json:"period"`
+	PeriodType   *string  `json:"periodType"`
+	TeamId       *float64 `json:"teamId"`
+	TeamTricode  *string  `json:"teamTricode"`
+	ActionType   *string  `json:"actionType"`
+	SubType      *string  `json:"subType"`
+	Qualifiers   []string `json:"qualifiers"`
+	PersonId     *float64 `json:"personId"`
+	X            *float64 `json:"x"`
+	Y            *float64 `json:"y"`
+	XLegacy      *float64 `json:"xLegacy"`
+	YLegacy      *float64 `json:"yLegacy"`
+	Possession   *float64 `json:"possession"`
+	ScoreHome    *string  `json:"scoreHome"`
+	ScoreAway    *string  `json:"scoreAway"`
+	Edited       *string  `json:"edited"`
+	OrderNumber  *float64 `json:"orderNumber"`
+	IsFieldGoal  *float64 `json:"isFieldGoal"`
+	ShotResult   *string  `json:"shotResult"`
+	ShotDistance *float64 `json:"shotDistance"`
+	Description  *string  `json:"description"`
+	PlayerName   *string  `json:"playerName"`
+	PlayerNameI  *string  `json:"playerNameI"`
+}
+
+func (a *LivePlayByPlayAction) ClockSeconds() (float64, error) {
+	if a.Clock == nil {
+		return 0, fmt.Errorf("action has no clock")
+	}
+	return parseGameClock(*a.Clock)
+}
+
+func (a *LivePlayByPlayAction) is(actionType, subType string) bool {
+	return a.ActionType != nil && *a.ActionType == actionType && a.SubType != nil && *a.SubType == subType
+}
+
+type LivePlayByPlayData struct {
+	GameId  *string                `json:"gameId"`
+	Actions []LivePlayByPlayAction `json:"actions"`
+}
+
+// liveGet fetches a liveData feed. It returns errors rather than panicking like
+// curl: feeds 403 until a game's first action and a poller has to survive a
+// flaky connection over a whole game.
+func liveGet(ctx context.Context, url string, v any) error {
+	req := initNBAReq(url)
+	req = req.WithContext(ctx)
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+// LiveScoreboard returns today's games in the league.
+func LiveScoreboard(ctx context.Context, league League) (*LiveScoreboardData, error) {
+	resp := struct {
+		Scoreboard *LiveScoreboardData `json:"scoreboard"`
+	}{}
+	if err := liveGet(ctx, fmt.Sprintf("%s/scoreboard/todaysScoreboard_%s.json", liveDataURL, league), &resp); err != nil {
+		return nil, err
+	}
+	if resp.Scoreboard == nil {
+		return nil, fmt.Errorf("live scoreboard: response is missing \"scoreboard\"")
+	}
+	return resp.Scoreboard, nil
+}
+
+func LiveBoxScore(ctx context.Context, gameID string) (*LiveBoxScoreData, error) {
+	resp := struct {
+		Game *LiveBoxScoreData `json:"game"`
+	}{}
+	if err := liveGet(ctx, fmt.Sprintf("%s/boxscore/boxscore_%s.json", liveDataURL, gameID), &resp); err != nil {
+		return nil, err
+	}
+	if resp.Game == nil {
+		return nil, fmt.Errorf("live boxscore: response is missing \"game\"")
+	}
+	return resp.Game, nil
+}
+
+func LivePlayByPlay(ctx context.Context, gameID string) (*LivePlayByPlayData, error) {
+	resp := struct {
+		Game *LivePlayByPlayData `json:"game"`
+	}{}
+	if err := liveGet(ctx, fmt.Sprintf("%s/playbyplay/playbyplay_%s.json", liveDataURL, gameID), &resp); err != nil {
+		return nil, err
+	}
+	if resp.Game == nil {
+		return nil, fmt.Errorf("live playbyplay: response is missing \"game\"")
+	}
+	return resp.Game, nil
+}
+
+type LiveEventType int
+
+const (
+	LiveEventAction LiveEventType = iota
+	LiveEventScore
+	LiveEventPeriodEnd
+	LiveEventFinal
+	LiveEventError
+)
+
+func (t LiveEventType) String() string {
+	switch t {
+	case LiveEventAction:
+		return "action"
+	case LiveEventScore:
+		return "score"
+	case LiveEventPeriodEnd:
+		return "period end"
+	case LiveEventFinal:
+		return "final"
+	case LiveEventError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// LiveEvent is a change noticed by PollLiveGame. Action is the action that
+// caused it, Home and Away are the score after it and Err is only set for
+// LiveEventError.
+type LiveEvent struct {
+	Type   LiveEventType
+	GameID string
+	Action LivePlayByPlayAction
+	Home   string
+	Away   string
+	Err    error
+}
+
+// PollLiveGame polls a game's live play-by-play every interval and sends an
+// event for each new action, score change, end of period and the end of the
+// game. Actions already in the feed on the first poll are sent too, so
+// starting after the game is over still produces a LiveEventFinal. Failed
+// polls are sent as LiveEventError and polling carries on. The channel is
+// closed after the final event or when ctx is cancelled.
+func PollLiveGame(ctx context.Context, gameID string, interval time.Duration) <-chan LiveEvent {
+	events := make(chan LiveEvent)
+	go func() {
+		defer close(events)
+		send := func(e LiveEvent) bool {
+			e.GameID = gameID
+			select {
+			case events <- e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		lastAction := -1.0
+		home, away := "0", "0"
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			pbp, err := LivePlayByPlay(ctx, gameID)
+			if err != nil && ctx.Err() == nil {
+				if !send(LiveEvent{Type: LiveEventError, Err: err}) {
+					return
+				}
+			}
+			if pbp != nil {
+				for _, action := range pbp.Actions {
+					if action.ActionNumber == nil || *action.ActionNumber <= lastAction {
+						continue
+					}
+					lastAction = *action.ActionNumber
+
+					scored := false
+					if action.ScoreHome != nil && action.ScoreAway != nil && (*action.ScoreHome != home || *action.ScoreAway != away) {
+						home, away = *action.ScoreHome, *action.ScoreAway
+						scored = true
+					}
+					if !send(LiveEvent{Type: LiveEventAction, Action: action, Home: home, Away: away}) {
+						return
+					}
+					if scored && !send(LiveEvent{Type: LiveEventScore, Action: action, Home: home, Away: away}) {
+						return
+					}
+					if action.is("period", "end") {
+						if !send(LiveEvent{Type: LiveEventPeriodEnd, Action: action, Home: home, Away: away}) {
+							return
+						}
+					}
+					if action.is("game", "end") {
+						send(LiveEvent{Type: LiveEventFinal, Action: action, Home: home, Away: away})
+						return
+					}
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return events
+}
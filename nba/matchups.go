@@ -0,0 +1,128 @@
+package nba
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// boxscorematchupsv3 lists, for every offensive player, each defender who
+// guarded them and what happened while they did.
+
+type BoxScoreMatchupsV3Data struct {
+	GameId     *string                `json:"gameId"`
+	AwayTeamId *float64               `json:"awayTeamId"`
+	HomeTeamId *float64               `json:"homeTeamId"`
+	HomeTeam   BoxScoreMatchupsV3Team `json:"homeTeam"`
+	AwayTeam   BoxScoreMatchupsV3Team `json:"awayTeam"`
+}
+
+type BoxScoreMatchupsV3Team struct {
+	TeamId      *float64                   `json:"teamId"`
+	TeamCity    *string                    `json:"teamCity"`
+	TeamName    *string                    `json:"teamName"`
+	TeamTricode *string                    `json:"teamTricode"`
+	TeamSlug    *string                    `json:"teamSlug"`
+	Players     []BoxScoreMatchupsV3Player `json:"players"`
+}
+
+// BoxScoreMatchupsV3Player is an offensive player and the defenders who
+// guarded them.
+type BoxScoreMatchupsV3Player struct {
+	PersonId   *float64                    `json:"personId"`
+	FirstName  *string                     `json:"firstName"`
+	FamilyName *string                     `json:"familyName"`
+	NameI      *string                     `json:"nameI"`
+	PlayerSlug *string                     `json:"playerSlug"`
+	Position   *string                     `json:"position"`
+	Comment    *string                     `json:"comment"`
+	JerseyNum  *string                     `json:"jerseyNum"`
+	Matchups   []BoxScoreMatchupsV3Matchup `json:"matchups"`
+}
+
+// BoxScoreMatchupsV3Matchup is a defender and the offensive player's stats
+// while that defender was on them.
+type BoxScoreMatchupsV3Matchup struct {
+	PersonId   *float64                `json:"personId"`
+	FirstName  *string                 `json:"firstName"`
+	FamilyName *string                 `json:"familyName"`
+	NameI      *string                 `json:"nameI"`
+	PlayerSlug *string                 `json:"playerSlug"`
+	JerseyNum  *string                 `json:"jerseyNum"`
+	Statistics BoxScoreMatchupsV3Stats `json:"statistics"`
+}
+
+type BoxScoreMatchupsV3Stats struct {
+	MatchupMinutes                 *string  `json:"matchupMinutes"`
+	MatchupMinutesSort             *float64 `json:"matchupMinutesSort"`
+	PartialPossessions             *float64 `json:"partialPossessions"`
+	PercentageDefenderTotalTime    *float64 `json:"percentageDefenderTotalTime"`
+	PercentageOffensiveTotalTime   *float64 `json:"percentageOffensiveTotalTime"`
+	PercentageTotalTimeBothOn      *float64 `json:"percentageTotalTimeBothOn"`
+	SwitchesOn                     *float64 `json:"switchesOn"`
+	PlayerPoints                   *float64 `json:"playerPoints"`
+	TeamPoints                     *float64 `json:"teamPoints"`
+	MatchupAssists                 *float64 `json:"matchupAssists"`
+	MatchupPotentialAssists        *float64 `json:"matchupPotentialAssists"`
+	MatchupTurnovers               *float64 `json:"matchupTurnovers"`
+	MatchupBlocks                  *float64 `json:"matchupBlocks"`
+	MatchupFieldGoalsMade          *float64 `json:"matchupFieldGoalsMade"`
+	MatchupFieldGoalsAttempted     *float64 `json:"matchupFieldGoalsAttempted"`
+	MatchupFieldGoalsPercentage    *float64 `json:"matchupFieldGoalsPercentage"`
+	MatchupThreePointersMade       *float64 `json:"matchupThreePointersMade"`
+	MatchupThreePointersAttempted  *float64 `json:"matchupThreePointersAttempted"`
+	MatchupThreePointersPercentage *float64 `json:"matchupThreePointersPercentage"`
+	HelpBlocks                     *float64 `json:"helpBlocks"`
+	HelpFieldGoalsMade             *float64 `json:"helpFieldGoalsMade"`
+	HelpFieldGoalsAttempted        *float64 `json:"helpFieldGoalsAttempted"`
+	HelpFieldGoalsPercentage       *float64 `json:"helpFieldGoalsPercentage"`
+	MatchupFreeThrowsMade          *float64 `json:"matchupFreeThrowsMade"`
+	MatchupFreeThrowsAttempted     *float64 `json:"matchupFreeThrowsAttempted"`
+	ShootingFouls                  *float64 `json:"shootingFouls"`
+}
+
+func BoxScoreMatchupsV3(gameID string) (*BoxScoreMatchupsV3Data, error) {
+	data := BoxScoreMatchupsV3Data{}
+	if err := fetchV3("boxscorematchupsv3", "boxScoreMatchups", gameID, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// DefensiveMatchup is one offensive player a defender guarded.
+type DefensiveMatchup struct {
+	OffensivePlayerID   int
+	OffensivePlayerName string
+	Statistics          BoxScoreMatchupsV3Stats
+}
+
+// GuardedBy returns every offensive player defenderID guarded, most points
+// allowed first.
+func (d *BoxScoreMatchupsV3Data) GuardedBy(defenderID int) ([]DefensiveMatchup, error) {
+	matchups := []DefensiveMatchup{}
+	for _, team := range []BoxScoreMatchupsV3Team{d.HomeTeam, d.AwayTeam} {
+		for _, off := range team.Players {
+			for _, m := range off.Matchups {
+				if m.PersonId == nil || int(*m.PersonId) != defenderID || off.PersonId == nil {
+					continue
+				}
+				name := ""
+				if off.FirstName != nil && off.FamilyName != nil {
+					name = *off.FirstName + " " + *off.FamilyName
+				}
+				matchups = append(matchups, DefensiveMatchup{
+					OffensivePlayerID:   int(*off.PersonId),
+					OffensivePlayerName: name,
+					Statistics:          m.Statistics,
+				})
+			}
+		}
+	}
+	if len(matchups) == 0 {
+		return nil, fmt.Errorf("player %d has no defensive matchups", defenderID)
+	}
+	slices.SortStableFunc(matchups, func(a, b DefensiveMatchup) int {
+		return cmp.Compare(value(b.Statistics.PlayerPoints), value(a.Statistics.PlayerPoints))
+	})
+	return matchups, nil
+}
@@ -5,12 +5,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"strings"
 	"time"
 )
 
 func init() {
-	fmt.Println("The New York Knickerbockers are named after pants")
+	fmt.Fprintln(os.Stderr, "The New York Knickerbockers are named after pants")
 }
 
 type CommonAllPlayersResp struct {
@@ -51,11 +52,11 @@ func initNBAReq(url string) *http.Request {
 	return req
 }
 
-func CommonAllPlayers() []CommonAllPlayer {
-	url := "https://stats.nba.com/stats/commonallplayers?LeagueID=00&Season=2023-24&IsOnlyCurrentSeason=0"
+func CommonAllPlayers(league League) []CommonAllPlayer {
+	url := fmt.Sprintf("https://stats.nba.com/stats/commonallplayers?LeagueID=%s&Season=%s&IsOnlyCurrentSeason=0", league, league.CurrentSeason(time.Now()))
 	req := initNBAReq(url)
 
-	fmt.Println("Sending CommonAllPlayers request...")
+	fmt.Fprintln(os.Stderr, "Sending CommonAllPlayers request...")
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
@@ -143,10 +144,13 @@ type LeagueGameFinderGame struct {
 // jalen brunson ID: 1628973
 // knicks teamID: 1610612752
 
-func LeagueGameFinderByPlayerID(playerID int) ([]LeagueGameFinderGame, error) {
-	url := fmt.Sprintf("https://stats.nba.com/stats/leaguegamefinder?PlayerOrTeam=P&PlayerID=%d", playerID)
+func LeagueGameFinderByPlayerID(league League, playerID int) ([]LeagueGameFinderGame, error) {
+	url := fmt.Sprintf("https://stats.nba.com/stats/leaguegamefinder?LeagueID=%s&PlayerOrTeam=P&PlayerID=%d", league, playerID)
 	req := initNBAReq(url)
-	body := curl(req)
+	body, err := get(req)
+	if err != nil {
+		return []LeagueGameFinderGame{}, err
+	}
 
 	unmarshalledBody := LeagueGameFinderByPlayerIDResp{}
 	if err := json.Unmarshal(body, &unmarshalledBody); err != nil {
@@ -233,8 +237,8 @@ func LeagueGameFinderByPlayerID(playerID int) ([]LeagueGameFinderGame, error) {
 	return res, nil
 }
 
-func LeagueGameFinderByTeamID(teamID int) []LeagueGameFinderGame {
-	url := fmt.Sprintf("https://stats.nba.com/stats/leaguegamefinder?Season=2024-25&PlayerOrTeam=T&TeamID=%d", teamID)
+func LeagueGameFinderByTeamID(league League, teamID int, season string) []LeagueGameFinderGame {
+	url := fmt.Sprintf("https://stats.nba.com/stats/leaguegamefinder?LeagueID=%s&Season=%s&PlayerOrTeam=T&TeamID=%d", league, season, teamID)
 	req := initNBAReq(url)
 	body := curl(req)
 
@@ -726,16 +730,29 @@ type VideoDetailsAssetPlaylistEntry struct {
 }
 
 type VideoDetailAsset struct {
-	GameID      *string
-	EventID     *float64
-	Year        *float64
-	Month       *string
-	Day         *string
-	Description *string
-	Uuid        *string
-	LargeUrl    *string
-	MedUrl      *string
-	SmallUrl    *string
+	GameID               *string
+	EventID              *float64
+	PlayerID             *float64
+	TeamID               *float64
+	ContextMeasure       VideoDetailsAssetContextMeasure
+	Year                 *float64
+	Month                *string
+	Day                  *string
+	Period               *float64
+	HomeAbbreviation     *string
+	VisitingAbbreviation *string
+	HomePointsBefore     *float64
+	HomePointsAfter      *float64
+	VisitingPointsBefore *float64
+	VisitingPointsAfter  *float64
+	Description          *string
+	Uuid                 *string
+	LargeUrl             *string
+	LargeDur             *float64
+	MedUrl               *string
+	MedDur               *float64
+	SmallUrl             *string
+	SmallDur             *float64
 }
 
 type VideoDetailsAssetContextMeasure string
@@ -901,12 +918,20 @@ var VideoDetailsAssetContextMeasures = struct {
 }
 
 func VideoDetailsAsset(gameID string, playerID, teamID float64, contextMeasure VideoDetailsAssetContextMeasure) ([]VideoDetailAsset, error) {
-	url := fmt.Sprintf("https://stats.nba.com/stats/videodetailsasset?AheadBehind=&ClutchTime=&ContextFilter=&ContextMeasure=%s&DateFrom=&DateTo=&EndPeriod=&EndRange=&GameID=%s&GameSegment=&LastNGames=0&LeagueID=&Location=&Month=0&OpponentTeamID=0&Outcome=&Period=0&PlayerID=%d&PointDiff=&Position=&RangeType=&RookieYear=&Season=2024-25&SeasonSegment=&SeasonType=Regular+Season&StartPeriod=&StartRange=&TeamID=%d&VsConference=&VsDivision=", contextMeasure, gameID, int(playerID), int(teamID))
+	season, err := SeasonFromGameID(gameID)
+	if err != nil {
+		return []VideoDetailAsset{}, err
+	}
+	seasonType := strings.ReplaceAll(SeasonTypeFromGameID(gameID), " ", "+")
+	url := fmt.Sprintf("https://stats.nba.com/stats/videodetailsasset?AheadBehind=&ClutchTime=&ContextFilter=&ContextMeasure=%s&DateFrom=&DateTo=&EndPeriod=&EndRange=&GameID=%s&GameSegment=&LastNGames=0&LeagueID=%s&Location=&Month=0&OpponentTeamID=0&Outcome=&Period=0&PlayerID=%d&PointDiff=&Position=&RangeType=&RookieYear=&Season=%s&SeasonSegment=&SeasonType=%s&StartPeriod=&StartRange=&TeamID=%d&VsConference=&VsDivision=", contextMeasure, gameID, LeagueFromGameID(gameID), int(playerID), season, seasonType, int(teamID))
 	req := initNBAReq(url)
-	body := curl(req)
+	body, err := get(req)
+	if err != nil {
+		return []VideoDetailAsset{}, err
+	}
 
 	unmarshalledBody := VideoDetailsAssetResp{}
-	err := json.Unmarshal(body, &unmarshalledBody)
+	err = json.Unmarshal(body, &unmarshalledBody)
 	if err != nil && strings.Contains(err.Error(), "invalid character '<'") {
 		// fmt.Println(string(body))
 		return []VideoDetailAsset{}, fmt.Errorf("%s: received html response, expected json", contextMeasure)
@@ -924,16 +949,29 @@ func VideoDetailsAsset(gameID string, playerID, teamID float64, contextMeasure V
 	res := make([]VideoDetailAsset, 0, len(Playlist))
 	for i := range Playlist {
 		entry := VideoDetailAsset{
-			GameID:      Playlist[i].GameID,
-			EventID:     Playlist[i].EventID,
-			Year:        Playlist[i].Year,
-			Month:       Playlist[i].Month,
-			Day:         Playlist[i].Day,
-			Description: Playlist[i].Description,
-			Uuid:        VideoUrls[i].Uuid,
-			SmallUrl:    VideoUrls[i].SmallUrl,
-			MedUrl:      VideoUrls[i].MedUrl,
-			LargeUrl:    VideoUrls[i].LargeUrl,
+			GameID:               Playlist[i].GameID,
+			EventID:              Playlist[i].EventID,
+			PlayerID:             &playerID,
+			TeamID:               &teamID,
+			ContextMeasure:       contextMeasure,
+			Year:                 Playlist[i].Year,
+			Month:                Playlist[i].Month,
+			Day:                  Playlist[i].Day,
+			Period:               Playlist[i].Period,
+			HomeAbbreviation:     Playlist[i].HomeAbbreviation,
+			VisitingAbbreviation: Playlist[i].VisitingAbbreviation,
+			HomePointsBefore:     Playlist[i].HomePointsBefore,
+			HomePointsAfter:      Playlist[i].HomePointsAfter,
+			VisitingPointsBefore: Playlist[i].VisitingPointsBefore,
+			VisitingPointsAfter:  Playlist[i].VisitingPointsAfter,
+			Description:          Playlist[i].Description,
+			Uuid:                 VideoUrls[i].Uuid,
+			SmallUrl:             VideoUrls[i].SmallUrl,
+			SmallDur:             VideoUrls[i].SmallDur,
+			MedUrl:               VideoUrls[i].MedUrl,
+			MedDur:               VideoUrls[i].MedDur,
+			LargeUrl:             VideoUrls[i].LargeUrl,
+			LargeDur:             VideoUrls[i].LargeDur,
 		}
 		if entry.LargeUrl == nil && entry.MedUrl == nil && entry.SmallUrl == nil {
 			continue
@@ -1017,32 +1055,47 @@ func (p *BoxScoreTraditionalV3Player) DidNotPlay() bool {
 
 func BoxScoreTraditionalV3(gameID string) (*BoxScoreTraditionalV3Data, error) {
 	url := fmt.Sprintf("https://stats.nba.com/stats/boxscoretraditionalv3?GameID=%s", gameID)
-	fmt.Println(url)
+	fmt.Fprintln(os.Stderr, url)
 	req := initNBAReq(url)
-	body := curl(req)
+	body, err := get(req)
+	if err != nil {
+		return nil, err
+	}
 
 	unmarshalled := BoxScoreTraditionalV3Resp{}
 	if err := json.Unmarshal(body, &unmarshalled); err != nil {
-		panic(err)
+		return nil, err
 	}
 	return &unmarshalled.BoxScoreTraditional, nil
 }
 
 var sem = make(chan int, 50)
 
+// SetMaxRequests limits how many requests can be in flight at once. Call it
+// before making any.
+func SetMaxRequests(n int) {
+	sem = make(chan int, n)
+}
+
 func curl(req *http.Request) []byte {
+	body, err := get(req)
+	if err != nil {
+		panic(err)
+	}
+	return body
+}
+
+// get makes a stats.nba.com request. Endpoints that return errors use it
+// rather than curl, so a failed request fails the caller instead of panicking
+// in whatever goroutine made it.
+func get(req *http.Request) ([]byte, error) {
 	sem <- 1
 	defer func() { <-sem }()
 	client := &http.Client{Timeout: 15 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		panic(err)
-	}
-	return body
+	return io.ReadAll(resp.Body)
 }
@@ -0,0 +1,99 @@
+package nba
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type PlayByPlayV3Resp struct {
+	Meta struct {
+		Version *float64 `json:"version"`
+		Request *string  `json:"request"`
+		Time    *string  `json:"time"`
+	} `json:"meta"`
+	Game PlayByPlayV3Data `json:"game"`
+}
+
+type PlayByPlayV3Data struct {
+	GameId         *string              `json:"gameId"`
+	VideoAvailable *float64             `json:"videoAvailable"`
+	Actions        []PlayByPlayV3Action `json:"actions"`
+}
+
+type PlayByPlayV3Action struct {
+	ActionNumber   *float64 `json:"actionNumber"`
+	Clock          *string  `json:"clock"`
+	Period         *float64 `json:"period"`
+	TeamId         *float64 `json:"teamId"`
+	TeamTricode    *string  `json:"teamTricode"`
+	PersonId       *float64 `json:"personId"`
+	PlayerName     *string  `json:"playerName"`
+	PlayerNameI    *string  `json:"playerNameI"`
+	XLegacy        *float64 `json:"xLegacy"`
+	YLegacy        *float64 `json:"yLegacy"`
+	ShotDistance   *float64 `json:"shotDistance"`
+	ShotResult     *string  `json:"shotResult"`
+	IsFieldGoal    *float64 `json:"isFieldGoal"`
+	ScoreHome      *string  `json:"scoreHome"`
+	ScoreAway      *string  `json:"scoreAway"`
+	PointsTotal    *float64 `json:"pointsTotal"`
+	Location       *string  `json:"location"`
+	Description    *string  `json:"description"`
+	ActionType     *string  `json:"actionType"`
+	SubType        *string  `json:"subType"`
+	VideoAvailable *float64 `json:"videoAvailable"`
+	ShotValue      *float64 `json:"shotValue"`
+	ActionId       *float64 `json:"actionId"`
+}
+
+// ClockSeconds parses the ISO 8601 game clock ("PT11M42.00S") into the number
+// of seconds left in the period.
+func (a *PlayByPlayV3Action) ClockSeconds() (float64, error) {
+	if a.Clock == nil {
+		return 0, fmt.Errorf("action has no clock")
+	}
+	return parseGameClock(*a.Clock)
+}
+
+func parseGameClock(clock string) (float64, error) {
+	var minutes, seconds float64
+	if _, err := fmt.Sscanf(clock, "PT%fM%fS", &minutes, &seconds); err != nil {
+		return 0, fmt.Errorf("unexpected game clock %q: %v", clock, err)
+	}
+	return minutes*60 + seconds, nil
+}
+
+// IsShot reports whether the action is a made or missed field goal. Shot
+// distance and location are only meaningful for shots.
+func (a *PlayByPlayV3Action) IsShot() bool {
+	return a.IsFieldGoal != nil && *a.IsFieldGoal == 1
+}
+
+// Action looks up an action by its actionNumber, which is the same number
+// VideoDetailsAsset reports as a clip's EventID.
+func (d *PlayByPlayV3Data) Action(actionNumber int) (PlayByPlayV3Action, bool) {
+	for _, a := range d.Actions {
+		if a.ActionNumber != nil && int(*a.ActionNumber) == actionNumber {
+			return a, true
+		}
+	}
+	return PlayByPlayV3Action{}, false
+}
+
+func PlayByPlayV3(gameID string) (*PlayByPlayV3Data, error) {
+	url := fmt.Sprintf("https://stats.nba.com/stats/playbyplayv3?GameID=%s&StartPeriod=0&EndPeriod=0", gameID)
+	req := initNBAReq(url)
+	body, err := get(req)
+	if err != nil {
+		return nil, err
+	}
+
+	unmarshalled := PlayByPlayV3Resp{}
+	if err := json.Unmarshal(body, &unmarshalled); err != nil && strings.Contains(err.Error(), "invalid character '<'") {
+		return nil, fmt.Errorf("playbyplayv3: received html response, expected json")
+	} else if err != nil {
+		return nil, err
+	}
+	return &unmarshalled.Game, nil
+}
@@ -0,0 +1,269 @@
+package nba
+
+import (
+	"fmt"
+)
+
+type CommonPlayerInfoData struct {
+	PersonID                     *float64
+	FirstName                    *string
+	LastName                     *string
+	DisplayFirstLast             *string
+	DisplayLastCommaFirst        *string
+	DisplayFILast                *string
+	PlayerSlug                   *string
+	Birthdate                    *string
+	School                       *string
+	Country                      *string
+	LastAffiliation              *string
+	Height                       *string
+	Weight                       *string
+	SeasonExp                    *float64
+	Jersey                       *string
+	Position                     *string
+	RosterStatus                 *string
+	GamesPlayedCurrentSeasonFlag *string
+	TeamID                       *float64
+	TeamName                     *string
+	TeamAbbreviation             *string
+	TeamCode                     *string
+	TeamCity                     *string
+	PlayerCode                   *string
+	FromYear                     *float64
+	ToYear                       *float64
+	DLeagueFlag                  *string
+	NBAFlag                      *string
+	GamesPlayedFlag              *string
+	DraftYear                    *string
+	DraftRound                   *string
+	DraftNumber                  *string
+	Greatest75Flag               *string
+}
+
+func CommonPlayerInfo(league League, playerID int) (CommonPlayerInfoData, error) {
+	url := fmt.Sprintf("https://stats.nba.com/stats/commonplayerinfo?LeagueID=%s&PlayerID=%d", league, playerID)
+	sets, err := fetchResultSets("commonplayerinfo", url)
+	if err != nil {
+		return CommonPlayerInfoData{}, err
+	}
+
+	expectedHeaders := []string{
+		"PERSON_ID",
+		"FIRST_NAME",
+		"LAST_NAME",
+		"DISPLAY_FIRST_LAST",
+		"DISPLAY_LAST_COMMA_FIRST",
+		"DISPLAY_FI_LAST",
+		"PLAYER_SLUG",
+		"BIRTHDATE",
+		"SCHOOL",
+		"COUNTRY",
+		"LAST_AFFILIATION",
+		"HEIGHT",
+		"WEIGHT",
+		"SEASON_EXP",
+		"JERSEY",
+		"POSITION",
+		"ROSTERSTATUS",
+		"GAMES_PLAYED_CURRENT_SEASON_FLAG",
+		"TEAM_ID",
+		"TEAM_NAME",
+		"TEAM_ABBREVIATION",
+		"TEAM_CODE",
+		"TEAM_CITY",
+		"PLAYERCODE",
+		"FROM_YEAR",
+		"TO_YEAR",
+		"DLEAGUE_FLAG",
+		"NBA_FLAG",
+		"GAMES_PLAYED_FLAG",
+		"DRAFT_YEAR",
+		"DRAFT_ROUND",
+		"DRAFT_NUMBER",
+		"GREATEST_75_FLAG",
+	}
+	set, err := resultSet("commonplayerinfo", sets, "CommonPlayerInfo", expectedHeaders)
+	if err != nil {
+		return CommonPlayerInfoData{}, err
+	}
+	if len(set.RowSet) == 0 {
+		return CommonPlayerInfoData{}, fmt.Errorf("commonplayerinfo: no player with id %d", playerID)
+	}
+
+	raw := set.RowSet[0]
+	return CommonPlayerInfoData{
+		PersonID:                     maybe[float64](raw[0]),
+		FirstName:                    maybe[string](raw[1]),
+		LastName:                     maybe[string](raw[2]),
+		DisplayFirstLast:             maybe[string](raw[3]),
+		DisplayLastCommaFirst:        maybe[string](raw[4]),
+		DisplayFILast:                maybe[string](raw[5]),
+		PlayerSlug:                   maybe[string](raw[6]),
+		Birthdate:                    maybe[string](raw[7]),
+		School:                       maybe[string](raw[8]),
+		Country:                      maybe[string](raw[9]),
+		LastAffiliation:              maybe[string](raw[10]),
+		Height:                       maybe[string](raw[11]),
+		Weight:                       maybe[string](raw[12]),
+		SeasonExp:                    maybe[float64](raw[13]),
+		Jersey:                       maybe[string](raw[14]),
+		Position:                     maybe[string](raw[15]),
+		RosterStatus:                 maybe[string](raw[16]),
+		GamesPlayedCurrentSeasonFlag: maybe[string](raw[17]),
+		TeamID:                       maybe[float64](raw[18]),
+		TeamName:                     maybe[string](raw[19]),
+		TeamAbbreviation:             maybe[string](raw[20]),
+		TeamCode:                     maybe[string](raw[21]),
+		TeamCity:                     maybe[string](raw[22]),
+		PlayerCode:                   maybe[string](raw[23]),
+		FromYear:                     maybe[float64](raw[24]),
+		ToYear:                       maybe[float64](raw[25]),
+		DLeagueFlag:                  maybe[string](raw[26]),
+		NBAFlag:                      maybe[string](raw[27]),
+		GamesPlayedFlag:              maybe[string](raw[28]),
+		DraftYear:                    maybe[string](raw[29]),
+		DraftRound:                   maybe[string](raw[30]),
+		DraftNumber:                  maybe[string](raw[31]),
+		Greatest75Flag:               maybe[string](raw[32]),
+	}, nil
+}
+
+type CommonTeamRosterPlayer struct {
+	TeamID      *float64
+	Season      *string
+	LeagueID    *string
+	Player      *string
+	Nickname    *string
+	PlayerSlug  *string
+	Num         *string
+	Position    *string
+	Height      *string
+	Weight      *string
+	BirthDate   *string
+	Age         *float64
+	Exp         *string
+	School      *string
+	PlayerID    *float64
+	HowAcquired *string
+}
+
+type CommonTeamRosterCoach struct {
+	TeamID          *float64
+	Season          *string
+	CoachID         *float64
+	FirstName       *string
+	LastName        *string
+	CoachName       *string
+	IsAssistant     *float64
+	CoachType       *string
+	SortSequence    *float64
+	SubSortSequence *float64
+}
+
+type CommonTeamRosterData struct {
+	Players []CommonTeamRosterPlayer
+	Coaches []CommonTeamRosterCoach
+}
+
+// CommonTeamRoster returns a team's roster and coaching staff for a season,
+// e.g. "2024-25".
+func CommonTeamRoster(league League, teamID int, season string) (CommonTeamRosterData, error) {
+	url := fmt.Sprintf("https://stats.nba.com/stats/commonteamroster?LeagueID=%s&Season=%s&TeamID=%d", league, season, teamID)
+	sets, err := fetchResultSets("commonteamroster", url)
+	if err != nil {
+		return CommonTeamRosterData{}, err
+	}
+
+	playerHeaders := []string{
+		"TeamID",
+		"SEASON",
+		"LeagueID",
+		"PLAYER",
+		"NICKNAME",
+		"PLAYER_SLUG",
+		"NUM",
+		"POSITION",
+		"HEIGHT",
+		"WEIGHT",
+		"BIRTH_DATE",
+		"AGE",
+		"EXP",
+		"SCHOOL",
+		"PLAYER_ID",
+		"HOW_ACQUIRED",
+	}
+	playerSet, err := resultSet("commonteamroster", sets, "CommonTeamRoster", playerHeaders)
+	if err != nil {
+		return CommonTeamRosterData{}, err
+	}
+	coachHeaders := []string{
+		"TEAM_ID",
+		"SEASON",
+		"COACH_ID",
+		"FIRST_NAME",
+		"LAST_NAME",
+		"COACH_NAME",
+		"IS_ASSISTANT",
+		"COACH_TYPE",
+		"SORT_SEQUENCE",
+		"SUB_SORT_SEQUENCE",
+	}
+	coachSet, err := resultSet("commonteamroster", sets, "Coaches", coachHeaders)
+	if err != nil {
+		return CommonTeamRosterData{}, err
+	}
+
+	roster := CommonTeamRosterData{
+		Players: make([]CommonTeamRosterPlayer, len(playerSet.RowSet)),
+		Coaches: make([]CommonTeamRosterCoach, len(coachSet.RowSet)),
+	}
+	for i, raw := range playerSet.RowSet {
+		roster.Players[i] = CommonTeamRosterPlayer{
+			TeamID:      maybe[float64](raw[0]),
+			Season:      maybe[string](raw[1]),
+			LeagueID:    maybe[string](raw[2]),
+			Player:      maybe[string](raw[3]),
+			Nickname:    maybe[string](raw[4]),
+			PlayerSlug:  maybe[string](raw[5]),
+			Num:         maybe[string](raw[6]),
+			Position:    maybe[string](raw[7]),
+			Height:      maybe[string](raw[8]),
+			Weight:      maybe[string](raw[9]),
+			BirthDate:   maybe[string](raw[10]),
+			Age:         maybe[float64](raw[11]),
+			Exp:         maybe[string](raw[12]),
+			School:      maybe[string](raw[13]),
+			PlayerID:    maybe[float64](raw[14]),
+			HowAcquired: maybe[string](raw[15]),
+		}
+	}
+	for i, raw := range coachSet.RowSet {
+		roster.Coaches[i] = CommonTeamRosterCoach{
+			TeamID:          maybe[float64](raw[0]),
+			Season:          maybe[string](raw[1]),
+			CoachID:         maybe[float64](raw[2]),
+			FirstName:       maybe[string](raw[3]),
+			LastName:        maybe[string](raw[4]),
+			CoachName:       maybe[string](raw[5]),
+			IsAssistant:     maybe[float64](raw[6]),
+			CoachType:       maybe[string](raw[7]),
+			SortSequence:    maybe[float64](raw[8]),
+			SubSortSequence: maybe[float64](raw[9]),
+		}
+	}
+	return roster, nil
+}
+
+// SeasonFromID turns a game finder SEASON_ID ("22024", the season type
+// followed by the year the season starts in) into the league's season string
+// ("2024-25", or "2024" for the WNBA).
+func SeasonFromID(league League, seasonID string) (string, error) {
+	if len(seasonID) < 4 {
+		return "", fmt.Errorf("unexpected season id %q", seasonID)
+	}
+	var year int
+	if _, err := fmt.Sscanf(seasonID[len(seasonID)-4:], "%d", &year); err != nil {
+		return "", fmt.Errorf("unexpected season id %q: %v", seasonID, err)
+	}
+	return league.Season(year), nil
+}
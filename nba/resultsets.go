@@ -0,0 +1,70 @@
+package nba
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ResultSet is the table format most stats.nba.com endpoints respond with: a
+// named list of headers and rows of untyped values in header order.
+type ResultSet struct {
+	Name    string          `json:"name"`
+	Headers []string        `json:"headers"`
+	RowSet  [][]interface{} `json:"rowSet"`
+}
+
+type ResultSetsResp struct {
+	ResultSets []ResultSet `json:"resultSets"`
+}
+
+// fetchResultSets requests url and returns its result sets keyed by name.
+func fetchResultSets(endpoint, url string) (map[string]ResultSet, error) {
+	req := initNBAReq(url)
+	body, err := get(req)
+	if err != nil {
+		return nil, err
+	}
+
+	unmarshalled := ResultSetsResp{}
+	if err := json.Unmarshal(body, &unmarshalled); err != nil && strings.Contains(err.Error(), "invalid character '<'") {
+		return nil, fmt.Errorf("%s: received html response, expected json", endpoint)
+	} else if err != nil {
+		return nil, err
+	}
+
+	sets := map[string]ResultSet{}
+	for _, set := range unmarshalled.ResultSets {
+		sets[set.Name] = set
+	}
+	return sets, nil
+}
+
+// resultSet looks up a named result set and checks its headers.
+func resultSet(endpoint string, sets map[string]ResultSet, name string, expectedHeaders []string) (ResultSet, error) {
+	set, ok := sets[name]
+	if !ok {
+		return set, fmt.Errorf("%s: response is missing the %s result set", endpoint, name)
+	}
+	if err := validateHeaders(expectedHeaders, set.Headers); err != nil {
+		return set, fmt.Errorf("%s: %s: %v", endpoint, name, err)
+	}
+	return set, nil
+}
+
+// resultSetWithPrefix is resultSet for endpoints whose trailing columns (ranks,
+// fantasy points, ...) change from season to season. Only the leading
+// columns we read are checked.
+func resultSetWithPrefix(endpoint string, sets map[string]ResultSet, name string, expectedHeaders []string) (ResultSet, error) {
+	set, ok := sets[name]
+	if !ok {
+		return set, fmt.Errorf("%s: response is missing the %s result set", endpoint, name)
+	}
+	if len(set.Headers) < len(expectedHeaders) {
+		return set, fmt.Errorf("%s: %s: expected at least %d headers, found %d", endpoint, name, len(expectedHeaders), len(set.Headers))
+	}
+	if err := validateHeaders(expectedHeaders, set.Headers[:len(expectedHeaders)]); err != nil {
+		return set, fmt.Errorf("%s: %s: %v", endpoint, name, err)
+	}
+	return set, nil
+}
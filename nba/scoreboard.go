@@ -0,0 +1,222 @@
+package nba
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type GameStatus int
+
+const (
+	GameStatusUnknown    GameStatus = 0
+	GameStatusScheduled  GameStatus = 1
+	GameStatusInProgress GameStatus = 2
+	GameStatusFinal      GameStatus = 3
+)
+
+func (s GameStatus) String() string {
+	switch s {
+	case GameStatusScheduled:
+		return "scheduled"
+	case GameStatusInProgress:
+		return "live"
+	case GameStatusFinal:
+		return "final"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseGameStatus is the inverse of GameStatus.String.
+func ParseGameStatus(s string) (GameStatus, error) {
+	for _, status := range []GameStatus{GameStatusScheduled, GameStatusInProgress, GameStatusFinal} {
+		if strings.EqualFold(s, status.String()) {
+			return status, nil
+		}
+	}
+	return GameStatusUnknown, fmt.Errorf("unknown game status %q, expected scheduled, live or final", s)
+}
+
+func gameStatus(status *float64) GameStatus {
+	if status == nil {
+		return GameStatusUnknown
+	}
+	return GameStatus(*status)
+}
+
+type ScoreboardV3Resp struct {
+	Meta struct {
+		Version *float64 `json:"version"`
+		Request *string  `json:"request"`
+		Time    *string  `json:"time"`
+	} `json:"meta"`
+	Scoreboard ScoreboardV3Data `json:"scoreboard"`
+}
+
+type ScoreboardV3Data struct {
+	GameDate   *string            `json:"gameDate"`
+	LeagueId   *string            `json:"leagueId"`
+	LeagueName *string            `json:"leagueName"`
+	Games      []ScoreboardV3Game `json:"games"`
+}
+
+type ScoreboardV3Game struct {
+	GameId            *string          `json:"gameId"`
+	GameCode          *string          `json:"gameCode"`
+	GameStatus        *float64         `json:"gameStatus"`
+	GameStatusText    *string          `json:"gameStatusText"`
+	Period            *float64         `json:"period"`
+	GameClock         *string          `json:"gameClock"`
+	GameTimeUTC       *string          `json:"gameTimeUTC"`
+	GameEt            *string          `json:"gameEt"`
+	RegulationPeriods *float64         `json:"regulationPeriods"`
+	SeriesText        *string          `json:"seriesText"`
+	HomeTeam          ScoreboardV3Team `json:"homeTeam"`
+	AwayTeam          ScoreboardV3Team `json:"awayTeam"`
+}
+
+type ScoreboardV3Team struct {
+	TeamId      *float64 `json:"teamId"`
+	TeamName    *string  `json:"teamName"`
+	TeamCity    *string  `json:"teamCity"`
+	TeamTricode *string  `json:"teamTricode"`
+	TeamSlug    *string  `json:"teamSlug"`
+	Wins        *float64 `json:"wins"`
+	Losses      *float64 `json:"losses"`
+	Score       *float64 `json:"score"`
+	Periods     []struct {
+		Period     *float64 `json:"period"`
+		PeriodType *string  `json:"periodType"`
+		Score      *float64 `json:"score"`
+	} `json:"periods"`
+}
+
+func (g *ScoreboardV3Game) Status() GameStatus {
+	return gameStatus(g.GameStatus)
+}
+
+// HasTeam reports whether the team with the given tricode (NYK, BOS, ...)
+// plays in the game.
+func (g *ScoreboardV3Game) HasTeam(tricode string) bool {
+	return hasTricode(g.HomeTeam.TeamTricode, tricode) || hasTricode(g.AwayTeam.TeamTricode, tricode)
+}
+
+func hasTricode(teamTricode *string, tricode string) bool {
+	return teamTricode != nil && strings.EqualFold(*teamTricode, tricode)
+}
+
+// ScoreboardV3 returns every game played (or to be played) on the given date.
+func ScoreboardV3(league League, date time.Time) (*ScoreboardV3Data, error) {
+	url := fmt.Sprintf("https://stats.nba.com/stats/scoreboardv3?GameDate=%s&LeagueID=%s", date.Format("2006-01-02"), league)
+	req := initNBAReq(url)
+	body, err := get(req)
+	if err != nil {
+		return nil, err
+	}
+
+	unmarshalled := ScoreboardV3Resp{}
+	if err := json.Unmarshal(body, &unmarshalled); err != nil && strings.Contains(err.Error(), "invalid character '<'") {
+		return nil, fmt.Errorf("scoreboardv3: received html response, expected json")
+	} else if err != nil {
+		return nil, err
+	}
+	return &unmarshalled.Scoreboard, nil
+}
+
+type ScheduleLeagueV2Resp struct {
+	Meta struct {
+		Version *float64 `json:"version"`
+		Request *string  `json:"request"`
+		Time    *string  `json:"time"`
+	} `json:"meta"`
+	LeagueSchedule ScheduleLeagueV2Data `json:"leagueSchedule"`
+}
+
+type ScheduleLeagueV2Data struct {
+	SeasonYear *string `json:"seasonYear"`
+	LeagueId   *string `json:"leagueId"`
+	GameDates  []struct {
+		GameDate *string                `json:"gameDate"`
+		Games    []ScheduleLeagueV2Game `json:"games"`
+	} `json:"gameDates"`
+}
+
+type ScheduleLeagueV2Game struct {
+	GameId          *string              `json:"gameId"`
+	GameCode        *string              `json:"gameCode"`
+	GameStatus      *float64             `json:"gameStatus"`
+	GameStatusText  *string              `json:"gameStatusText"`
+	GameSequence    *float64             `json:"gameSequence"`
+	GameDateEst     *string              `json:"gameDateEst"`
+	GameTimeEst     *string              `json:"gameTimeEst"`
+	GameDateTimeEst *string              `json:"gameDateTimeEst"`
+	GameDateTimeUTC *string              `json:"gameDateTimeUTC"`
+	WeekNumber      *float64             `json:"weekNumber"`
+	GameLabel       *string              `json:"gameLabel"`
+	GameSubLabel    *string              `json:"gameSubLabel"`
+	SeriesText      *string              `json:"seriesText"`
+	ArenaName       *string              `json:"arenaName"`
+	ArenaCity       *string              `json:"arenaCity"`
+	ArenaState      *string              `json:"arenaState"`
+	PostponedStatus *string              `json:"postponedStatus"`
+	HomeTeam        ScheduleLeagueV2Team `json:"homeTeam"`
+	AwayTeam        ScheduleLeagueV2Team `json:"awayTeam"`
+}
+
+type ScheduleLeagueV2Team struct {
+	TeamId      *float64 `json:"teamId"`
+	TeamName    *string  `json:"teamName"`
+	TeamCity    *string  `json:"teamCity"`
+	TeamTricode *string  `json:"teamTricode"`
+	TeamSlug    *string  `json:"teamSlug"`
+	Wins        *float64 `json:"wins"`
+	Losses      *float64 `json:"losses"`
+	Score       *float64 `json:"score"`
+	Seed        *float64 `json:"seed"`
+}
+
+func (g *ScheduleLeagueV2Game) Status() GameStatus {
+	return gameStatus(g.GameStatus)
+}
+
+func (g *ScheduleLeagueV2Game) HasTeam(tricode string) bool {
+	return hasTricode(g.HomeTeam.TeamTricode, tricode) || hasTricode(g.AwayTeam.TeamTricode, tricode)
+}
+
+// Date is the day the game is played on, Eastern time.
+func (g *ScheduleLeagueV2Game) Date() (time.Time, error) {
+	if g.GameDateEst == nil {
+		return time.Time{}, fmt.Errorf("game %v has no date", g.GameId)
+	}
+	return time.Parse("2006-01-02", (*g.GameDateEst)[:min(len(*g.GameDateEst), 10)])
+}
+
+// Games flattens the schedule into one list of games in date order.
+func (d *ScheduleLeagueV2Data) Games() []ScheduleLeagueV2Game {
+	games := []ScheduleLeagueV2Game{}
+	for _, date := range d.GameDates {
+		games = append(games, date.Games...)
+	}
+	return games
+}
+
+// ScheduleLeagueV2 returns the full schedule for a season, e.g. "2024-25",
+// including preseason and playoff games.
+func ScheduleLeagueV2(league League, season string) (*ScheduleLeagueV2Data, error) {
+	url := fmt.Sprintf("https://stats.nba.com/stats/scheduleleaguev2?LeagueID=%s&Season=%s", league, season)
+	req := initNBAReq(url)
+	body, err := get(req)
+	if err != nil {
+		return nil, err
+	}
+
+	unmarshalled := ScheduleLeagueV2Resp{}
+	if err := json.Unmarshal(body, &unmarshalled); err != nil && strings.Contains(err.Error(), "invalid character '<'") {
+		return nil, fmt.Errorf("scheduleleaguev2: received html response, expected json")
+	} else if err != nil {
+		return nil, err
+	}
+	return &unmarshalled.LeagueSchedule, nil
+}
@@ -0,0 +1,191 @@
+package nba
+
+import (
+	"fmt"
+	"net/url"
+)
+
+type ShotChartDetailShot struct {
+	GridType          *string
+	GameID            *string
+	GameEventID       *float64
+	PlayerID          *float64
+	PlayerName        *string
+	TeamID            *float64
+	TeamName          *string
+	Period            *float64
+	MinutesRemaining  *float64
+	SecondsRemaining  *float64
+	EventType         *string
+	ActionType        *string
+	ShotType          *string
+	ShotZoneBasic     *string
+	ShotZoneArea      *string
+	ShotZoneRange     *string
+	ShotDistance      *float64
+	LocX              *float64
+	LocY              *float64
+	ShotAttemptedFlag *float64
+	ShotMadeFlag      *float64
+	GameDate          *string
+	HomeTeam          *string
+	VisitingTeam      *string
+}
+
+// Made reports whether the shot went in.
+func (s *ShotChartDetailShot) Made() bool {
+	return s.ShotMadeFlag != nil && *s.ShotMadeFlag == 1
+}
+
+type ShotChartDetailLeagueAverage struct {
+	GridType      *string
+	ShotZoneBasic *string
+	ShotZoneArea  *string
+	ShotZoneRange *string
+	FGA           *float64
+	FGM           *float64
+	FG_PCT        *float64
+}
+
+type ShotChartDetailData struct {
+	Shots          []ShotChartDetailShot
+	LeagueAverages []ShotChartDetailLeagueAverage
+}
+
+// SeasonTypeFromGameID reads the season type out of the digit after the
+// league (0012400001 is a preseason game, 0022400001 a regular season game,
+// ...).
+func SeasonTypeFromGameID(gameID string) string {
+	if len(gameID) < 3 {
+		return "Regular Season"
+	}
+	switch gameID[2] {
+	case '1':
+		return "Pre Season"
+	case '3':
+		return "All Star"
+	case '4':
+		return "Playoffs"
+	case '5':
+		return "PlayIn"
+	case '6':
+		return "IST"
+	default:
+		return "Regular Season"
+	}
+}
+
+// ShotChartDetail returns every field goal attempt a player took in a game.
+// LOC_X and LOC_Y are in tenths of a foot with the hoop at the origin.
+func ShotChartDetail(gameID string, playerID, teamID int, season string) (ShotChartDetailData, error) {
+	params := url.Values{}
+	params.Set("ContextMeasure", "FGA")
+	params.Set("GameID", gameID)
+	params.Set("LastNGames", "0")
+	params.Set("LeagueID", string(LeagueFromGameID(gameID)))
+	params.Set("Month", "0")
+	params.Set("OpponentTeamID", "0")
+	params.Set("Period", "0")
+	params.Set("PlayerID", fmt.Sprintf("%d", playerID))
+	params.Set("PlayerPosition", "")
+	params.Set("RookieYear", "")
+	params.Set("Season", season)
+	params.Set("SeasonSegment", "")
+	params.Set("SeasonType", SeasonTypeFromGameID(gameID))
+	params.Set("TeamID", fmt.Sprintf("%d", teamID))
+	params.Set("VsConference", "")
+	params.Set("VsDivision", "")
+	sets, err := fetchResultSets("shotchartdetail", "https://stats.nba.com/stats/shotchartdetail?"+params.Encode())
+	if err != nil {
+		return ShotChartDetailData{}, err
+	}
+
+	shotHeaders := []string{
+		"GRID_TYPE",
+		"GAME_ID",
+		"GAME_EVENT_ID",
+		"PLAYER_ID",
+		"PLAYER_NAME",
+		"TEAM_ID",
+		"TEAM_NAME",
+		"PERIOD",
+		"MINUTES_REMAINING",
+		"SECONDS_REMAINING",
+		"EVENT_TYPE",
+		"ACTION_TYPE",
+		"SHOT_TYPE",
+		"SHOT_ZONE_BASIC",
+		"SHOT_ZONE_AREA",
+		"SHOT_ZONE_RANGE",
+		"SHOT_DISTANCE",
+		"LOC_X",
+		"LOC_Y",
+		"SHOT_ATTEMPTED_FLAG",
+		"SHOT_MADE_FLAG",
+		"GAME_DATE",
+		"HTM",
+		"VTM",
+	}
+	shotSet, err := resultSet("shotchartdetail", sets, "Shot_Chart_Detail", shotHeaders)
+	if err != nil {
+		return ShotChartDetailData{}, err
+	}
+	averageHeaders := []string{
+		"GRID_TYPE",
+		"SHOT_ZONE_BASIC",
+		"SHOT_ZONE_AREA",
+		"SHOT_ZONE_RANGE",
+		"FGA",
+		"FGM",
+		"FG_PCT",
+	}
+	averageSet, err := resultSet("shotchartdetail", sets, "LeagueAverages", averageHeaders)
+	if err != nil {
+		return ShotChartDetailData{}, err
+	}
+
+	data := ShotChartDetailData{
+		Shots:          make([]ShotChartDetailShot, len(shotSet.RowSet)),
+		LeagueAverages: make([]ShotChartDetailLeagueAverage, len(averageSet.RowSet)),
+	}
+	for i, raw := range shotSet.RowSet {
+		data.Shots[i] = ShotChartDetailShot{
+			GridType:          maybe[string](raw[0]),
+			GameID:            maybe[string](raw[1]),
+			GameEventID:       maybe[float64](raw[2]),
+			PlayerID:          maybe[float64](raw[3]),
+			PlayerName:        maybe[string](raw[4]),
+			TeamID:            maybe[float64](raw[5]),
+			TeamName:          maybe[string](raw[6]),
+			Period:            maybe[float64](raw[7]),
+			MinutesRemaining:  maybe[float64](raw[8]),
+			SecondsRemaining:  maybe[float64](raw[9]),
+			EventType:         maybe[string](raw[10]),
+			ActionType:        maybe[string](raw[11]),
+			ShotType:          maybe[string](raw[12]),
+			ShotZoneBasic:     maybe[string](raw[13]),
+			ShotZoneArea:      maybe[string](raw[14]),
+			ShotZoneRange:     maybe[string](raw[15]),
+			ShotDistance:      maybe[float64](raw[16]),
+			LocX:              maybe[float64](raw[17]),
+			LocY:              maybe[float64](raw[18]),
+			ShotAttemptedFlag: maybe[float64](raw[19]),
+			ShotMadeFlag:      maybe[float64](raw[20]),
+			GameDate:          maybe[string](raw[21]),
+			HomeTeam:          maybe[string](raw[22]),
+			VisitingTeam:      maybe[string](raw[23]),
+		}
+	}
+	for i, raw := range averageSet.RowSet {
+		data.LeagueAverages[i] = ShotChartDetailLeagueAverage{
+			GridType:      maybe[string](raw[0]),
+			ShotZoneBasic: maybe[string](raw[1]),
+			ShotZoneArea:  maybe[string](raw[2]),
+			ShotZoneRange: maybe[string](raw[3]),
+			FGA:           maybe[float64](raw[4]),
+			FGM:           maybe[float64](raw[5]),
+			FG_PCT:        maybe[float64](raw[6]),
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,397 @@
+package nba
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type BoxScoreSummaryV2GameSummary struct {
+	GameDateEst     *string
+	GameSequence    *float64
+	GameID          *string
+	GameStatusID    *float64
+	GameStatusText  *string
+	Gamecode        *string
+	HomeTeamID      *float64
+	VisitorTeamID   *float64
+	Season          *string
+	LivePeriod      *float64
+	LivePCTime      *string
+	NatlTVBroadcast *string
+}
+
+type BoxScoreSummaryV2OtherStats struct {
+	LeagueID         *string
+	TeamID           *float64
+	TeamAbbreviation *string
+	TeamCity         *string
+	PtsPaint         *float64
+	Pts2ndChance     *float64
+	PtsFastBreak     *float64
+	LargestLead      *float64
+	LeadChanges      *float64
+	TimesTied        *float64
+	TeamTurnovers    *float64
+	TotalTurnovers   *float64
+	TeamRebounds     *float64
+	PtsOffTO         *float64
+}
+
+type BoxScoreSummaryV2Official struct {
+	OfficialID *float64
+	FirstName  *string
+	LastName   *string
+	JerseyNum  *string
+}
+
+type BoxScoreSummaryV2InactivePlayer struct {
+	PlayerID         *float64
+	FirstName        *string
+	LastName         *string
+	JerseyNum        *string
+	TeamID           *float64
+	TeamCity         *string
+	TeamName         *string
+	TeamAbbreviation *string
+}
+
+type BoxScoreSummaryV2GameInfo struct {
+	GameDate   *string
+	Attendance *float64
+	GameTime   *string
+}
+
+type BoxScoreSummaryV2LineScore struct {
+	GameDateEst      *string
+	GameSequence     *float64
+	GameID           *string
+	TeamID           *float64
+	TeamAbbreviation *string
+	TeamCityName     *string
+	TeamNickname     *string
+	TeamWinsLosses   *string
+	// Quarters holds PTS_QTR1-4 and OT holds PTS_OT1-10. Overtimes that
+	// weren't played are 0.
+	Quarters [4]*float64
+	OT       [10]*float64
+	PTS      *float64
+}
+
+// Periods returns the points scored in every period that was played,
+// overtimes included.
+func (l BoxScoreSummaryV2LineScore) Periods() []float64 {
+	periods := []float64{}
+	for _, q := range l.Quarters {
+		periods = append(periods, value(q))
+	}
+	for _, ot := range l.OT {
+		if value(ot) == 0 {
+			break
+		}
+		periods = append(periods, value(ot))
+	}
+	return periods
+}
+
+type BoxScoreSummaryV2Data struct {
+	Summary    BoxScoreSummaryV2GameSummary
+	Info       BoxScoreSummaryV2GameInfo
+	OtherStats []BoxScoreSummaryV2OtherStats
+	Officials  []BoxScoreSummaryV2Official
+	Inactives  []BoxScoreSummaryV2InactivePlayer
+	LineScores []BoxScoreSummaryV2LineScore
+}
+
+// HomeLineScore and AwayLineScore pick a side of the line score using the
+// game summary's home and visitor ids.
+func (d *BoxScoreSummaryV2Data) HomeLineScore() (BoxScoreSummaryV2LineScore, bool) {
+	return d.lineScore(d.Summary.HomeTeamID)
+}
+
+func (d *BoxScoreSummaryV2Data) AwayLineScore() (BoxScoreSummaryV2LineScore, bool) {
+	return d.lineScore(d.Summary.VisitorTeamID)
+}
+
+func (d *BoxScoreSummaryV2Data) lineScore(teamID *float64) (BoxScoreSummaryV2LineScore, bool) {
+	if teamID == nil {
+		return BoxScoreSummaryV2LineScore{}, false
+	}
+	for _, l := range d.LineScores {
+		if l.TeamID != nil && *l.TeamID == *teamID {
+			return l, true
+		}
+	}
+	return BoxScoreSummaryV2LineScore{}, false
+}
+
+// BoxScoreSummaryV2 returns a game's line score, officials, inactive players,
+// attendance and lead changes.
+func BoxScoreSummaryV2(gameID string) (*BoxScoreSummaryV2Data, error) {
+	url := fmt.Sprintf("https://stats.nba.com/stats/boxscoresummaryv2?GameID=%s", gameID)
+	sets, err := fetchResultSets("boxscoresummaryv2", url)
+	if err != nil {
+		return nil, err
+	}
+	data := BoxScoreSummaryV2Data{}
+
+	set, err := resultSetWithPrefix("boxscoresummaryv2", sets, "GameSummary", []string{
+		"GAME_DATE_EST",
+		"GAME_SEQUENCE",
+		"GAME_ID",
+		"GAME_STATUS_ID",
+		"GAME_STATUS_TEXT",
+		"GAMECODE",
+		"HOME_TEAM_ID",
+		"VISITOR_TEAM_ID",
+		"SEASON",
+		"LIVE_PERIOD",
+		"LIVE_PC_TIME",
+		"NATL_TV_BROADCASTER_ABBREVIATION",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(set.RowSet) != 1 {
+		return nil, fmt.Errorf("boxscoresummaryv2: expected 1 GameSummary row, found %d", len(set.RowSet))
+	}
+	raw := set.RowSet[0]
+	data.Summary = BoxScoreSummaryV2GameSummary{
+		GameDateEst:     maybe[string](raw[0]),
+		GameSequence:    maybe[float64](raw[1]),
+		GameID:          maybe[string](raw[2]),
+		GameStatusID:    maybe[float64](raw[3]),
+		GameStatusText:  maybe[string](raw[4]),
+		Gamecode:        maybe[string](raw[5]),
+		HomeTeamID:      maybe[float64](raw[6]),
+		VisitorTeamID:   maybe[float64](raw[7]),
+		Season:          maybe[string](raw[8]),
+		LivePeriod:      maybe[float64](raw[9]),
+		LivePCTime:      maybe[string](raw[10]),
+		NatlTVBroadcast: maybe[string](raw[11]),
+	}
+
+	set, err = resultSet("boxscoresummaryv2", sets, "GameInfo", []string{
+		"GAME_DATE",
+		"ATTENDANCE",
+		"GAME_TIME",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(set.RowSet) > 0 {
+		raw := set.RowSet[0]
+		data.Info = BoxScoreSummaryV2GameInfo{
+			GameDate:   maybe[string](raw[0]),
+			Attendance: maybe[float64](raw[1]),
+			GameTime:   maybe[string](raw[2]),
+		}
+	}
+
+	set, err = resultSet("boxscoresummaryv2", sets, "OtherStats", []string{
+		"LEAGUE_ID",
+		"TEAM_ID",
+		"TEAM_ABBREVIATION",
+		"TEAM_CITY",
+		"PTS_PAINT",
+		"PTS_2ND_CHANCE",
+		"PTS_FB",
+		"LARGEST_LEAD",
+		"LEAD_CHANGES",
+		"TIMES_TIED",
+		"TEAM_TURNOVERS",
+		"TOTAL_TURNOVERS",
+		"TEAM_REBOUNDS",
+		"PTS_OFF_TO",
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, raw := range set.RowSet {
+		data.OtherStats = append(data.OtherStats, BoxScoreSummaryV2OtherStats{
+			LeagueID:         maybe[string](raw[0]),
+			TeamID:           maybe[float64](raw[1]),
+			TeamAbbreviation: maybe[string](raw[2]),
+			TeamCity:         maybe[string](raw[3]),
+			PtsPaint:         maybe[float64](raw[4]),
+			Pts2ndChance:     maybe[float64](raw[5]),
+			PtsFastBreak:     maybe[float64](raw[6]),
+			LargestLead:      maybe[float64](raw[7]),
+			LeadChanges:      maybe[float64](raw[8]),
+			TimesTied:        maybe[float64](raw[9]),
+			TeamTurnovers:    maybe[float64](raw[10]),
+			TotalTurnovers:   maybe[float64](raw[11]),
+			TeamRebounds:     maybe[float64](raw[12]),
+			PtsOffTO:         maybe[float64](raw[13]),
+		})
+	}
+
+	set, err = resultSet("boxscoresummaryv2", sets, "Officials", []string{
+		"OFFICIAL_ID",
+		"FIRST_NAME",
+		"LAST_NAME",
+		"JERSEY_NUM",
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, raw := range set.RowSet {
+		data.Officials = append(data.Officials, BoxScoreSummaryV2Official{
+			OfficialID: maybe[float64](raw[0]),
+			FirstName:  maybe[string](raw[1]),
+			LastName:   maybe[string](raw[2]),
+			JerseyNum:  maybe[string](raw[3]),
+		})
+	}
+
+	set, err = resultSet("boxscoresummaryv2", sets, "InactivePlayers", []string{
+		"PLAYER_ID",
+		"FIRST_NAME",
+		"LAST_NAME",
+		"JERSEY_NUM",
+		"TEAM_ID",
+		"TEAM_CITY",
+		"TEAM_NAME",
+		"TEAM_ABBREVIATION",
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, raw := range set.RowSet {
+		data.Inactives = append(data.Inactives, BoxScoreSummaryV2InactivePlayer{
+			PlayerID:         maybe[float64](raw[0]),
+			FirstName:        maybe[string](raw[1]),
+			LastName:         maybe[string](raw[2]),
+			JerseyNum:        maybe[string](raw[3]),
+			TeamID:           maybe[float64](raw[4]),
+			TeamCity:         maybe[string](raw[5]),
+			TeamName:         maybe[string](raw[6]),
+			TeamAbbreviation: maybe[string](raw[7]),
+		})
+	}
+
+	lineScoreHeaders := []string{
+		"GAME_DATE_EST",
+		"GAME_SEQUENCE",
+		"GAME_ID",
+		"TEAM_ID",
+		"TEAM_ABBREVIATION",
+		"TEAM_CITY_NAME",
+		"TEAM_NICKNAME",
+		"TEAM_WINS_LOSSES",
+		"PTS_QTR1",
+		"PTS_QTR2",
+		"PTS_QTR3",
+		"PTS_QTR4",
+	}
+	for i := 1; i <= 10; i++ {
+		lineScoreHeaders = append(lineScoreHeaders, fmt.Sprintf("PTS_OT%d", i))
+	}
+	lineScoreHeaders = append(lineScoreHeaders, "PTS")
+	set, err = resultSet("boxscoresummaryv2", sets, "LineScore", lineScoreHeaders)
+	if err != nil {
+		return nil, err
+	}
+	for _, raw := range set.RowSet {
+		line := BoxScoreSummaryV2LineScore{
+			GameDateEst:      maybe[string](raw[0]),
+			GameSequence:     maybe[float64](raw[1]),
+			GameID:           maybe[string](raw[2]),
+			TeamID:           maybe[float64](raw[3]),
+			TeamAbbreviation: maybe[string](raw[4]),
+			TeamCityName:     maybe[string](raw[5]),
+			TeamNickname:     maybe[string](raw[6]),
+			TeamWinsLosses:   maybe[string](raw[7]),
+			PTS:              maybe[float64](raw[22]),
+		}
+		for q := range line.Quarters {
+			line.Quarters[q] = maybe[float64](raw[8+q])
+		}
+		for ot := range line.OT {
+			line.OT[ot] = maybe[float64](raw[12+ot])
+		}
+		data.LineScores = append(data.LineScores, line)
+	}
+
+	return &data, nil
+}
+
+// boxscoresummaryv3 responds with nested json rather than result sets. It is
+// the only place the arena is available.
+
+type BoxScoreSummaryV3Data struct {
+	GameId         *string                   `json:"gameId"`
+	GameCode       *string                   `json:"gameCode"`
+	GameStatus     *float64                  `json:"gameStatus"`
+	GameStatusText *string                   `json:"gameStatusText"`
+	Period         *float64                  `json:"period"`
+	GameTimeUTC    *string                   `json:"gameTimeUTC"`
+	GameEt         *string                   `json:"gameEt"`
+	AwayTeamId     *float64                  `json:"awayTeamId"`
+	HomeTeamId     *float64                  `json:"homeTeamId"`
+	Duration       *string                   `json:"duration"`
+	Attendance     *float64                  `json:"attendance"`
+	Sellout        *float64                  `json:"sellout"`
+	Arena          BoxScoreSummaryV3Arena    `json:"arena"`
+	Officials      []BoxScoreSummaryV3Person `json:"officials"`
+	HomeTeam       BoxScoreSummaryV3Team     `json:"homeTeam"`
+	AwayTeam       BoxScoreSummaryV3Team     `json:"awayTeam"`
+}
+
+type BoxScoreSummaryV3Arena struct {
+	ArenaId       *float64 `json:"arenaId"`
+	ArenaName     *string  `json:"arenaName"`
+	ArenaCity     *string  `json:"arenaCity"`
+	ArenaState    *string  `json:"arenaState"`
+	ArenaCountry  *string  `json:"arenaCountry"`
+	ArenaTimezone *string  `json:"arenaTimezone"`
+}
+
+type BoxScoreSummaryV3Person struct {
+	PersonId   *float64 `json:"personId"`
+	Name       *string  `json:"name"`
+	NameI      *string  `json:"nameI"`
+	FirstName  *string  `json:"firstName"`
+	FamilyName *string  `json:"familyName"`
+	JerseyNum  *string  `json:"jerseyNum"`
+	Assignment *string  `json:"assignment"`
+}
+
+type BoxScoreSummaryV3Team struct {
+	TeamId      *float64                  `json:"teamId"`
+	TeamName    *string                   `json:"teamName"`
+	TeamCity    *string                   `json:"teamCity"`
+	TeamTricode *string                   `json:"teamTricode"`
+	TeamWins    *float64                  `json:"teamWins"`
+	TeamLosses  *float64                  `json:"teamLosses"`
+	Score       *float64                  `json:"score"`
+	Periods     []BoxScoreSummaryV3Period `json:"periods"`
+}
+
+type BoxScoreSummaryV3Period struct {
+	Period     *float64 `json:"period"`
+	PeriodType *string  `json:"periodType"`
+	Score      *float64 `json:"score"`
+}
+
+func BoxScoreSummaryV3(gameID string) (*BoxScoreSummaryV3Data, error) {
+	url := fmt.Sprintf("https://stats.nba.com/stats/boxscoresummaryv3?GameID=%s&LeagueID=%s", gameID, LeagueFromGameID(gameID))
+	req := initNBAReq(url)
+	body, err := get(req)
+	if err != nil {
+		return nil, err
+	}
+
+	unmarshalled := struct {
+		BoxScoreSummary *BoxScoreSummaryV3Data `json:"boxScoreSummary"`
+	}{}
+	if err := json.Unmarshal(body, &unmarshalled); err != nil && strings.Contains(err.Error(), "invalid character '<'") {
+		return nil, fmt.Errorf("boxscoresummaryv3: received html response, expected json")
+	} else if err != nil {
+		return nil, err
+	}
+	if unmarshalled.BoxScoreSummary == nil {
+		return nil, fmt.Errorf("boxscoresummaryv3: response is missing \"boxScoreSummary\"")
+	}
+	return unmarshalled.BoxScoreSummary, nil
+}
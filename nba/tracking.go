@@ -0,0 +1,218 @@
+package nba
+
+import "fmt"
+
+// Player tracking dashboards. Defended field goals are shots taken with the
+// player as the closest defender.
+
+type DefenseCategory string
+
+const (
+	DefenseCategoryOverall       DefenseCategory = "Overall"
+	DefenseCategory3Pointers     DefenseCategory = "3 Pointers"
+	DefenseCategory2Pointers     DefenseCategory = "2 Pointers"
+	DefenseCategoryLessThan6     DefenseCategory = "Less Than 6Ft"
+	DefenseCategoryLessThan10    DefenseCategory = "Less Than 10Ft"
+	DefenseCategoryGreaterThan15 DefenseCategory = "Greater Than 15Ft"
+)
+
+type LeagueDashPtDefendPlayer struct {
+	CloseDefPersonID           *float64
+	PlayerName                 *string
+	PlayerLastTeamID           *float64
+	PlayerLastTeamAbbreviation *string
+	PlayerPosition             *string
+	Age                        *float64
+	GP                         *float64
+	G                          *float64
+	Freq                       *float64
+	D_FGM                      *float64
+	D_FGA                      *float64
+	D_FG_PCT                   *float64
+	NormalFG_PCT               *float64
+	PCT_PlusMinus              *float64
+}
+
+// LeagueDashPtDefend returns every player's defended field goals in a
+// category, e.g. how well opponents shoot threes when they're the closest
+// defender.
+func LeagueDashPtDefend(league League, season, seasonType string, category DefenseCategory) ([]LeagueDashPtDefendPlayer, error) {
+	params := dashParams(league, season, seasonType, "Totals")
+	params.Set("DefenseCategory", string(category))
+	params.Set("PlayerID", "")
+	sets, err := fetchResultSets("leaguedashptdefend", "https://stats.nba.com/stats/leaguedashptdefend?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	expectedHeaders := []string{
+		"CLOSE_DEF_PERSON_ID",
+		"PLAYER_NAME",
+		"PLAYER_LAST_TEAM_ID",
+		"PLAYER_LAST_TEAM_ABBREVIATION",
+		"PLAYER_POSITION",
+		"AGE",
+		"GP",
+		"G",
+		"FREQ",
+		"D_FGM",
+		"D_FGA",
+		"D_FG_PCT",
+		"NORMAL_FG_PCT",
+		"PCT_PLUSMINUS",
+	}
+	set, err := resultSetWithPrefix("leaguedashptdefend", sets, "LeagueDashPTDefend", expectedHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	players := make([]LeagueDashPtDefendPlayer, len(set.RowSet))
+	for i, raw := range set.RowSet {
+		players[i] = LeagueDashPtDefendPlayer{
+			CloseDefPersonID:           maybe[float64](raw[0]),
+			PlayerName:                 maybe[string](raw[1]),
+			PlayerLastTeamID:           maybe[float64](raw[2]),
+			PlayerLastTeamAbbreviation: maybe[string](raw[3]),
+			PlayerPosition:             maybe[string](raw[4]),
+			Age:                        maybe[float64](raw[5]),
+			GP:                         maybe[float64](raw[6]),
+			G:                          maybe[float64](raw[7]),
+			Freq:                       maybe[float64](raw[8]),
+			D_FGM:                      maybe[float64](raw[9]),
+			D_FGA:                      maybe[float64](raw[10]),
+			D_FG_PCT:                   maybe[float64](raw[11]),
+			NormalFG_PCT:               maybe[float64](raw[12]),
+			PCT_PlusMinus:              maybe[float64](raw[13]),
+		}
+	}
+	return players, nil
+}
+
+type PlayerDashPtShotDefendCategory struct {
+	CloseDefPersonID *float64
+	GP               *float64
+	G                *float64
+	DefenseCategory  *string
+	Freq             *float64
+	D_FGM            *float64
+	D_FGA            *float64
+	D_FG_PCT         *float64
+	NormalFG_PCT     *float64
+	PCT_PlusMinus    *float64
+}
+
+// PlayerDashPtShotDefend breaks down one player's defended field goals by
+// category.
+func PlayerDashPtShotDefend(league League, playerID int, season, seasonType string) ([]PlayerDashPtShotDefendCategory, error) {
+	params := dashParams(league, season, seasonType, "Totals")
+	params.Set("PlayerID", fmt.Sprintf("%d", playerID))
+	sets, err := fetchResultSets("playerdashptshotdefend", "https://stats.nba.com/stats/playerdashptshotdefend?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	expectedHeaders := []string{
+		"CLOSE_DEF_PERSON_ID",
+		"GP",
+		"G",
+		"DEFENSE_CATEGORY",
+		"FREQ",
+		"D_FGM",
+		"D_FGA",
+		"D_FG_PCT",
+		"NORMAL_FG_PCT",
+		"PCT_PLUSMINUS",
+	}
+	set, err := resultSet("playerdashptshotdefend", sets, "DefendingShots", expectedHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := make([]PlayerDashPtShotDefendCategory, len(set.RowSet))
+	for i, raw := range set.RowSet {
+		categories[i] = PlayerDashPtShotDefendCategory{
+			CloseDefPersonID: maybe[float64](raw[0]),
+			GP:               maybe[float64](raw[1]),
+			G:                maybe[float64](raw[2]),
+			DefenseCategory:  maybe[string](raw[3]),
+			Freq:             maybe[float64](raw[4]),
+			D_FGM:            maybe[float64](raw[5]),
+			D_FGA:            maybe[float64](raw[6]),
+			D_FG_PCT:         maybe[float64](raw[7]),
+			NormalFG_PCT:     maybe[float64](raw[8]),
+			PCT_PlusMinus:    maybe[float64](raw[9]),
+		}
+	}
+	return categories, nil
+}
+
+type LeagueDashPtDefensePlayer struct {
+	PlayerID         *float64
+	PlayerName       *string
+	TeamID           *float64
+	TeamAbbreviation *string
+	GP               *float64
+	W                *float64
+	L                *float64
+	MIN              *float64
+	STL              *float64
+	BLK              *float64
+	DREB             *float64
+	DefRimFGM        *float64
+	DefRimFGA        *float64
+	DefRimFG_PCT     *float64
+}
+
+// LeagueDashPtDefense is the "Defense" player tracking dashboard: steals,
+// blocks, defensive rebounds and rim protection for every player.
+func LeagueDashPtDefense(league League, season, seasonType, perMode string) ([]LeagueDashPtDefensePlayer, error) {
+	params := dashParams(league, season, seasonType, perMode)
+	params.Set("PlayerOrTeam", "Player")
+	params.Set("PtMeasureType", "Defense")
+	sets, err := fetchResultSets("leaguedashptstats", "https://stats.nba.com/stats/leaguedashptstats?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	expectedHeaders := []string{
+		"PLAYER_ID",
+		"PLAYER_NAME",
+		"TEAM_ID",
+		"TEAM_ABBREVIATION",
+		"GP",
+		"W",
+		"L",
+		"MIN",
+		"STL",
+		"BLK",
+		"DREB",
+		"DEF_RIM_FGM",
+		"DEF_RIM_FGA",
+		"DEF_RIM_FG_PCT",
+	}
+	set, err := resultSetWithPrefix("leaguedashptstats", sets, "LeagueDashPtStats", expectedHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	players := make([]LeagueDashPtDefensePlayer, len(set.RowSet))
+	for i, raw := range set.RowSet {
+		players[i] = LeagueDashPtDefensePlayer{
+			PlayerID:         maybe[float64](raw[0]),
+			PlayerName:       maybe[string](raw[1]),
+			TeamID:           maybe[float64](raw[2]),
+			TeamAbbreviation: maybe[string](raw[3]),
+			GP:               maybe[float64](raw[4]),
+			W:                maybe[float64](raw[5]),
+			L:                maybe[float64](raw[6]),
+			MIN:              maybe[float64](raw[7]),
+			STL:              maybe[float64](raw[8]),
+			BLK:              maybe[float64](raw[9]),
+			DREB:             maybe[float64](raw[10]),
+			DefRimFGM:        maybe[float64](raw[11]),
+			DefRimFGA:        maybe[float64](raw[12]),
+			DefRimFG_PCT:     maybe[float64](raw[13]),
+		}
+	}
+	return players, nil
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"basketball/config"
+	"basketball/nba"
+
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+	"unicode"
+
+	flag "github.com/spf13/pflag"
+)
+
+// outTemplate is set by --out and overrides the config's output_template.
+var outTemplate string
+
+// outputFlags adds --out and --on-collision to a command that renders reels.
+// Call setOutput with what it returns once flags are parsed.
+func outputFlags(flags *flag.FlagSet) *string {
+	flags.StringVarP(&outTemplate, "out", "o", "", "where to save reels, a path or a template like {{.Player}}_{{.Date}}.mp4 (default output_dir/output_template from the config)")
+	return flags.String("on-collision", "", "when a reel's output path is taken: number, overwrite or fail (default the config's on_collision, number)")
+}
+
+func setOutput(onCollision string) error {
+	if outTemplate != "" {
+		if _, err := parseOutputTemplate(outTemplate); err != nil {
+			return &exitError{ExitUsage, err}
+		}
+	}
+	if onCollision != "" {
+		if err := config.Set("on_collision", onCollision, "--on-collision"); err != nil {
+			return &exitError{ExitUsage, err}
+		}
+	}
+	return nil
+}
+
+// OutputName is what an output template can use, e.g.
+// {{.Season}}/{{.Team}}/{{.Player}}_{{.Date}}_{{.Recipe}}.mp4. Every field is
+// safe to use in a file name.
+type OutputName struct {
+	Player   string
+	PlayerID int
+	Team     string
+	Opponent string
+	Matchup  string
+	GameID   string
+	Date     string
+	Season   string
+	Recipe   string
+}
+
+func parseOutputTemplate(text string) (*template.Template, error) {
+	t, err := template.New("output").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output template %q: %v", text, err)
+	}
+	return t, nil
+}
+
+// outputPath fills in the output template for the game's reel. A template
+// from --out is relative to the working directory and one from the config is
+// relative to output_dir.
+func outputPath(game nba.LeagueGameFinderGame, recipe Recipe) (string, error) {
+	text := outTemplate
+	if text == "" {
+		text = config.OutputTemplate
+		if !filepath.IsAbs(text) {
+			text = filepath.Join(config.OutputDir, text)
+		}
+	}
+	t, err := parseOutputTemplate(text)
+	if err != nil {
+		return "", err
+	}
+
+	name := OutputName{
+		Player:   fileSafe(deref(game.PlayerName)),
+		PlayerID: int(deref(game.PlayerId)),
+		Team:     fileSafe(deref(game.TeamAbbreviation)),
+		Opponent: fileSafe(opponent(deref(game.Matchup))),
+		Matchup:  fileSafe(deref(game.Matchup)),
+		GameID:   deref(game.GameID),
+		Date:     deref(game.GameDate),
+		Recipe:   fileSafe(recipe.Name),
+	}
+	if season, err := nba.SeasonFromID(nba.LeagueFromGameID(name.GameID), deref(game.SeasonID)); err == nil {
+		name.Season = season
+	}
+	b := strings.Builder{}
+	if err := t.Execute(&b, name); err != nil {
+		return "", err
+	}
+	path := b.String()
+	if filepath.Ext(path) != ".mp4" {
+		path += ".mp4"
+	}
+	return path, nil
+}
+
+// fileSafe keeps letters, digits, dots and dashes and turns everything else
+// into underscores, so "NYK @ BOS" becomes "NYK_BOS".
+func fileSafe(s string) string {
+	b := strings.Builder{}
+	underscore := false
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '-' {
+			b.WriteRune(r)
+			underscore = false
+		} else if !underscore && b.Len() > 0 {
+			b.WriteRune('_')
+			underscore = true
+		}
+	}
+	return strings.TrimRight(b.String(), "_")
+}
+
+// claimMu keeps concurrent jobs from claiming the same numbered path.
+var claimMu sync.Mutex
+
+// claimPath decides where a reel bound for path actually goes, according to
+// the config's on_collision: number it (name_2.mp4, ...), overwrite what's
+// there or fail. The path it returns is reserved with an empty file.
+func claimPath(path string) (string, error) {
+	claimMu.Lock()
+	defer claimMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	base := strings.TrimSuffix(path, ".mp4")
+	for n := 1; ; n++ {
+		candidate := path
+		if n > 1 {
+			candidate = fmt.Sprintf("%s_%d.mp4", base, n)
+		}
+		f, err := os.OpenFile(candidate, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			return candidate, f.Close()
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return "", err
+		}
+		switch config.OnCollision {
+		case "overwrite":
+			return candidate, nil
+		case "fail":
+			return "", fmt.Errorf("%s already exists (on_collision is fail)", candidate)
+		}
+	}
+}
+
+// Sidecar is written next to every reel, describing what's in it.
+type Sidecar struct {
+	Title    string
+	Statline string
+	Recipe   string
+	Rendered time.Time
+	Game     nba.LeagueGameFinderGame
+	Clips    []nba.VideoDetailAsset
+}
+
+func sidecarPath(reel string) string {
+	return strings.TrimSuffix(reel, ".mp4") + ".json"
+}
+
+// placeReel moves a rendered reel from rendered to its templated output path
+// and returns where it ended up.
+func placeReel(rendered string, game nba.LeagueGameFinderGame, recipe Recipe) (string, error) {
+	path, err := outputPath(game, recipe)
+	if err != nil {
+		return "", err
+	}
+	if path, err = claimPath(path); err != nil {
+		return "", err
+	}
+	if err := moveFile(rendered, path); err != nil {
+		_ = os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+// writeSidecar writes the sidecar for the reel at path.
+func writeSidecar(path string, game nba.LeagueGameFinderGame, recipe Recipe, clips []nba.VideoDetailAsset, statline string) error {
+	sidecar := Sidecar{Statline: statline, Recipe: recipe.Name, Rendered: time.Now(), Game: game, Clips: clips}
+	var err error
+	if sidecar.Title, err = title(game); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(sidecar, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(sidecarPath(path), append(b, '\n'), 0644)
+}
+
+func readSidecar(reel string) (Sidecar, error) {
+	sidecar := Sidecar{}
+	b, err := os.ReadFile(sidecarPath(reel))
+	if err != nil {
+		return sidecar, err
+	}
+	return sidecar, json.Unmarshal(b, &sidecar)
+}
+
+// reelStatline is the statline the reel's sidecar was written with, so an
+// upload doesn't fetch the game log again. Reels without a sidecar get theirs
+// worked out afresh.
+func reelStatline(game nba.LeagueGameFinderGame, reel string) (string, error) {
+	if sidecar, err := readSidecar(reel); err == nil && sidecar.Statline != "" {
+		return sidecar.Statline, nil
+	}
+	return statString(game, seasonGameLog(game))
+}
+
+// moveFile renames src to dst, copying when they're on different filesystems
+// as the temp dir and output dir often are.
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
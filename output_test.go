@@ -0,0 +1,175 @@
+package main
+
+import (
+	"basketball/config"
+
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileSafe(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"NYK @ BOS", "NYK_BOS"},
+		{"NYK vs. BOS", "NYK_vs._BOS"},
+		{"Jalen Brunson", "Jalen_Brunson"},
+		{"Nikola Jokić", "Nikola_Jokić"},
+		{"De'Aaron Fox", "De_Aaron_Fox"},
+		{"Karl-Anthony Towns", "Karl-Anthony_Towns"},
+		{"../../etc/passwd", ".._.._etc_passwd"},
+		{"  trailing!  ", "trailing"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := fileSafe(tt.in); got != tt.want {
+			t.Errorf("fileSafe(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestOutputPath(t *testing.T) {
+	defer func(dir, tmpl string) {
+		config.OutputDir, config.OutputTemplate, outTemplate = dir, tmpl, ""
+	}(config.OutputDir, config.OutputTemplate)
+	config.OutputDir = "/reels"
+
+	game := boxScore()
+	tests := []struct {
+		name    string
+		config  string
+		out     string
+		want    string
+		wantErr bool
+	}{
+		{"config default", "{{.Season}}/{{.Team}}/{{.Player}}_{{.Date}}_{{.Recipe}}.mp4", "", "/reels/2024-25/NYK/Jalen_Brunson_2024-10-22_highlights.mp4", false},
+		{"config template is under output_dir", "{{.Matchup}}", "", "/reels/NYK_BOS.mp4", false},
+		{"absolute config template", "/elsewhere/{{.GameID}}.mp4", "", "/elsewhere/0022400001.mp4", false},
+		{"--out is relative to the working directory", "{{.Player}}", "out/{{.PlayerID}}_{{.Opponent}}", "out/1628973_BOS.mp4", false},
+		{"--out without fields", "{{.Player}}", "reel.mp4", "reel.mp4", false},
+		{"unknown field", "{{.Nickname}}", "", "", true},
+		{"bad template", "{{.Player", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.OutputTemplate, outTemplate = tt.config, tt.out
+			got, err := outputPath(game, HighlightsRecipe)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got %q, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClaimPath(t *testing.T) {
+	defer func(policy string) { config.OnCollision = policy }(config.OnCollision)
+
+	tests := []struct {
+		policy string
+		// existing files, relative to the test's dir
+		existing []string
+		want     string
+		wantErr  bool
+	}{
+		{"number", nil, "reel.mp4", false},
+		{"number", []string{"reel.mp4"}, "reel_2.mp4", false},
+		{"number", []string{"reel.mp4", "reel_2.mp4", "reel_3.mp4"}, "reel_4.mp4", false},
+		{"overwrite", nil, "reel.mp4", false},
+		{"overwrite", []string{"reel.mp4"}, "reel.mp4", false},
+		{"fail", nil, "reel.mp4", false},
+		{"fail", []string{"reel.mp4"}, "", true},
+	}
+	for _, tt := range tests {
+		name := tt.policy + " with " + strings.Join(tt.existing, ",")
+		t.Run(name, func(t *testing.T) {
+			config.OnCollision = tt.policy
+			// claiming creates the reel's directory when it's missing
+			dir := filepath.Join(t.TempDir(), "2024-25")
+			if len(tt.existing) > 0 {
+				if err := os.Mkdir(dir, 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, f := range tt.existing {
+				if err := os.WriteFile(filepath.Join(dir, f), []byte("reel"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := claimPath(filepath.Join(dir, "reel.mp4"))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got %q, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if filepath.Base(got) != tt.want {
+				t.Errorf("got %q, want %s", got, tt.want)
+			}
+			if _, err := os.Stat(got); err != nil {
+				t.Errorf("claimed path wasn't reserved: %v", err)
+			}
+		})
+	}
+}
+
+func TestClaimPathNumbersConcurrentClaims(t *testing.T) {
+	defer func(policy string) { config.OnCollision = policy }(config.OnCollision)
+	config.OnCollision = "number"
+	path := filepath.Join(t.TempDir(), "reel.mp4")
+
+	claimed := make(chan string)
+	for range 5 {
+		go func() {
+			p, err := claimPath(path)
+			if err != nil {
+				t.Error(err)
+			}
+			claimed <- p
+		}()
+	}
+	seen := map[string]bool{}
+	for range 5 {
+		p := <-claimed
+		if seen[p] {
+			t.Errorf("%s was claimed twice", p)
+		}
+		seen[p] = true
+	}
+}
+
+func TestReelStatlineReadsSidecar(t *testing.T) {
+	game := boxScore()
+	reel := filepath.Join(t.TempDir(), "reel.mp4")
+	if err := writeSidecar(reel, game, HighlightsRecipe, nil, "30 Points, 10 Rebounds"); err != nil {
+		t.Fatal(err)
+	}
+	sidecar, err := readSidecar(reel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sidecar.Title != "Jalen Brunson | NYK @ BOS 10.22.2024" || sidecar.Recipe != HighlightsRecipe.Name {
+		t.Errorf("got title %q and recipe %q", sidecar.Title, sidecar.Recipe)
+	}
+	// with a sidecar the statline isn't fetched again
+	got, err := reelStatline(game, reel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "30 Points, 10 Rebounds" {
+		t.Errorf("got statline %q", got)
+	}
+}
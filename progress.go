@@ -0,0 +1,233 @@
+package main
+
+import (
+	"basketball/utils"
+
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// progress reports on clip queries, downloads, renders and uploads as they
+// run. On a terminal it draws a bar per task on stderr; otherwise it logs a
+// line as each task starts, passes every quarter and finishes.
+var progress = NewProgress(os.Stdout, os.Stderr, utils.IsTerminal(os.Stderr))
+
+type ProgressUnit int
+
+const (
+	ProgressCount ProgressUnit = iota
+	ProgressBytes
+	ProgressPercent
+)
+
+type Progress struct {
+	mu sync.Mutex
+	// stdout is where Printf and Println write, out is where the bars go
+	stdout io.Writer
+	out    io.Writer
+	tty    bool
+	tasks  []*ProgressTask
+	// drawn is how many bar lines are on screen below the last log line
+	drawn    int
+	lastDraw time.Time
+}
+
+func NewProgress(stdout, out io.Writer, tty bool) *Progress {
+	return &Progress{stdout: stdout, out: out, tty: tty}
+}
+
+// Printf prints to stdout without tearing the bars: they're cleared first and
+// drawn again below what was printed. Anything printed while tasks are running
+// should go through here, or Eprintf for errors and warnings.
+func (p *Progress) Printf(format string, a ...any) {
+	p.fprintf(p.stdout, format, a...)
+}
+
+func (p *Progress) Println(a ...any) {
+	p.fprintf(p.stdout, "%s", fmt.Sprintln(a...))
+}
+
+// Eprintf is Printf for stderr.
+func (p *Progress) Eprintf(format string, a ...any) {
+	p.fprintf(p.out, format, a...)
+}
+
+func (p *Progress) Eprintln(a ...any) {
+	p.fprintf(p.out, "%s", fmt.Sprintln(a...))
+}
+
+func (p *Progress) fprintf(w io.Writer, format string, a ...any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.tty {
+		p.clear()
+	}
+	fmt.Fprintf(w, format, a...)
+	if p.tty {
+		p.draw(true)
+	}
+}
+
+// ProgressTask is one thing being tracked, e.g. one player's downloads.
+// Totals can grow as work is discovered, like downloads learning their sizes.
+type ProgressTask struct {
+	p       *Progress
+	name    string
+	unit    ProgressUnit
+	current int64
+	total   int64
+	// quarter is the last quarter logged without a terminal
+	quarter int
+}
+
+// Start begins tracking a task. total can be 0 when it isn't known yet.
+func (p *Progress) Start(name string, unit ProgressUnit, total int64) *ProgressTask {
+	t := &ProgressTask{p: p, name: name, unit: unit, total: total}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tasks = append(p.tasks, t)
+	if p.tty {
+		p.draw(true)
+	} else {
+		fmt.Fprintf(p.out, "%s: started\n", name)
+	}
+	return t
+}
+
+func (t *ProgressTask) Add(n int64) {
+	t.update(func() { t.current += n })
+}
+
+func (t *ProgressTask) AddTotal(n int64) {
+	t.update(func() { t.total += n })
+}
+
+func (t *ProgressTask) Set(current int64) {
+	t.update(func() { t.current = current })
+}
+
+func (t *ProgressTask) update(f func()) {
+	p := t.p
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	f()
+	if p.tty {
+		p.draw(false)
+		return
+	}
+	if t.total > 0 {
+		if q := int(4 * t.current / t.total); q > t.quarter && q < 4 {
+			t.quarter = q
+			fmt.Fprintf(p.out, "%s: %s\n", t.name, t.status())
+		}
+	}
+}
+
+// Done stops tracking the task and leaves a line saying how it went.
+func (t *ProgressTask) Done(err error) {
+	p := t.p
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, task := range p.tasks {
+		if task == t {
+			p.tasks = append(p.tasks[:i], p.tasks[i+1:]...)
+			break
+		}
+	}
+	if err == nil && t.total > t.current {
+		t.current = t.total
+	}
+	line := fmt.Sprintf("%s: %s", t.name, t.status())
+	if err != nil {
+		line = fmt.Sprintf("%s: failed at %s", t.name, t.status())
+	}
+	if p.tty {
+		p.clear()
+		fmt.Fprintln(p.out, line)
+		p.draw(true)
+	} else {
+		fmt.Fprintln(p.out, line)
+	}
+}
+
+func (t *ProgressTask) status() string {
+	switch t.unit {
+	case ProgressBytes:
+		// downloads without a Content-Length can take current past total
+		if t.total > 0 && t.current <= t.total {
+			return fmt.Sprintf("%d%% (%s of %s)", 100*t.current/t.total, formatBytes(t.current), formatBytes(t.total))
+		}
+		return formatBytes(t.current)
+	case ProgressPercent:
+		return fmt.Sprintf("%d%%", t.current)
+	default:
+		if t.total > 0 {
+			return fmt.Sprintf("%d of %d", t.current, t.total)
+		}
+		return fmt.Sprint(t.current)
+	}
+}
+
+func (t *ProgressTask) fraction() float64 {
+	switch {
+	case t.unit == ProgressPercent:
+		return float64(t.current) / 100
+	case t.total > 0:
+		return min(1, float64(t.current)/float64(t.total))
+	}
+	return 0
+}
+
+// clear erases the bars so a log line can be written where they were.
+func (p *Progress) clear() {
+	if p.drawn > 0 {
+		fmt.Fprintf(p.out, "\x1b[%dA\x1b[J", p.drawn)
+		p.drawn = 0
+	}
+}
+
+// draw redraws every bar, at most ten times a second unless force is set.
+func (p *Progress) draw(force bool) {
+	if !force && time.Since(p.lastDraw) < 100*time.Millisecond {
+		return
+	}
+	p.lastDraw = time.Now()
+	p.clear()
+	const width = 30
+	for _, t := range p.tasks {
+		filled := int(t.fraction() * width)
+		filled = max(0, min(filled, width))
+		bar := strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+		fmt.Fprintf(p.out, "%-32.32s ▕%s▏ %s\n", t.name, bar, t.status())
+	}
+	p.drawn = len(p.tasks)
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// progressWriter counts what's written through it towards a task.
+type progressWriter struct {
+	w    io.Writer
+	task *ProgressTask
+}
+
+func (pw progressWriter) Write(b []byte) (int, error) {
+	n, err := pw.w.Write(b)
+	pw.task.Add(int64(n))
+	return n, err
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestProgressPlain(t *testing.T) {
+	stdout, out := bytes.Buffer{}, bytes.Buffer{}
+	p := NewProgress(&stdout, &out, false)
+	task := p.Start("download Jalen Brunson", ProgressBytes, 4096)
+	for range 4 {
+		task.Add(1024)
+	}
+	task.Done(nil)
+	failed := p.Start("clips Josh Hart", ProgressCount, 8)
+	failed.Add(3)
+	failed.Done(errors.New("timed out"))
+	p.Println("Upload successful :D!")
+	p.Eprintf("warning: %s\n", "clips don't add up")
+
+	want := []string{
+		"download Jalen Brunson: started",
+		"download Jalen Brunson: 25% (1.0 KiB of 4.0 KiB)",
+		"download Jalen Brunson: 50% (2.0 KiB of 4.0 KiB)",
+		"download Jalen Brunson: 75% (3.0 KiB of 4.0 KiB)",
+		"download Jalen Brunson: 100% (4.0 KiB of 4.0 KiB)",
+		"clips Josh Hart: started",
+		"clips Josh Hart: 3 of 8",
+		"clips Josh Hart: failed at 3 of 8",
+		"warning: clips don't add up",
+	}
+	if got := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if stdout.String() != "Upload successful :D!\n" {
+		t.Errorf("got stdout %q", stdout.String())
+	}
+}
+
+// TestProgressPrintKeepsBarsWhole checks a line printed on a terminal lands
+// above the bars: they're erased before it's printed and redrawn after.
+func TestProgressPrintKeepsBarsWhole(t *testing.T) {
+	term := bytes.Buffer{}
+	p := NewProgress(&term, &term, true)
+	task := p.Start("render Jalen Brunson", ProgressPercent, 100)
+	task.Set(40)
+	p.Printf("job %d is %s\n", 7, "done")
+
+	got := term.String()
+	i := strings.LastIndex(got, "job 7 is done\n")
+	if i < 0 {
+		t.Fatalf("line wasn't printed: %q", got)
+	}
+	before, after := got[:i], got[i+len("job 7 is done\n"):]
+	if !strings.HasSuffix(before, "\x1b[1A\x1b[J") {
+		t.Errorf("bars weren't erased before the line: %q", before)
+	}
+	if !strings.HasPrefix(after, "render Jalen Brunson") || !strings.HasSuffix(after, "40%\n") {
+		t.Errorf("bars weren't redrawn after the line: %q", after)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{5 * 1024 * 1024, "5.0 MiB"},
+		{3 << 30, "3.0 GiB"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.n); got != tt.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"basketball/utils"
+
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+
+	flag "github.com/spf13/pflag"
+)
+
+// assumeYes answers yes to every prompt, set by --yes.
+var assumeYes bool
+
+// noUpload stops team reels once they're rendered, set by --no-upload.
+var noUpload bool
+
+// MismatchPolicy is what to do when a player's clips can't be fetched or don't
+// add up to their box score.
+type MismatchPolicy string
+
+const (
+	MismatchPrompt MismatchPolicy = "prompt"
+	MismatchFail   MismatchPolicy = "fail"
+	MismatchWarn   MismatchPolicy = "warn"
+	MismatchSkip   MismatchPolicy = "skip"
+)
+
+func ParseMismatchPolicy(s string) (MismatchPolicy, error) {
+	for _, p := range []MismatchPolicy{MismatchPrompt, MismatchFail, MismatchWarn, MismatchSkip} {
+		if s == string(p) {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("unknown mismatch policy %q, expected prompt, fail, warn or skip", s)
+}
+
+var mismatchPolicy = MismatchPrompt
+
+// errSkipPlayer marks a job given up on under MismatchSkip. runJob cancels
+// these instead of failing them.
+var errSkipPlayer = errors.New("player skipped")
+
+// promptFlags adds --yes and --on-mismatch to a command. Call setMismatchPolicy
+// with the returned value once flags are parsed.
+func promptFlags(flags *flag.FlagSet) *string {
+	flags.BoolVarP(&assumeYes, "yes", "y", false, "answer yes to every prompt")
+	return flags.String("on-mismatch", string(MismatchPrompt), "when clips can't be fetched or don't match the box score: prompt, fail, warn or skip the player")
+}
+
+func setMismatchPolicy(s string) error {
+	p, err := ParseMismatchPolicy(s)
+	if err != nil {
+		return &exitError{ExitUsage, err}
+	}
+	mismatchPolicy = p
+	return nil
+}
+
+// confirm asks a yes or no question. Without a terminal to ask on it fails
+// rather than hang, unless --yes answered it already.
+func confirm(question string) (bool, error) {
+	if assumeYes {
+		fmt.Println(question, "yes (--yes)")
+		return true, nil
+	}
+	if !utils.IsTerminal(os.Stdin) {
+		return false, fmt.Errorf("can't ask %q without a terminal, pass --yes to answer yes", question)
+	}
+	fmt.Println(question, "(y/n)")
+	var input string
+	if _, err := fmt.Scan(&input); err != nil {
+		return false, err
+	}
+	return regexp.MustCompile("^[yY]").MatchString(input), nil
+}
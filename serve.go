@@ -0,0 +1,138 @@
+package main
+
+import (
+	"basketball/nba"
+
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+const serveUsage = `endpoints:
+  GET /jobs                              every job in the queue
+  GET /uploads                           the upload ledger
+  GET /statline?player=NAME              a player's statlines for the selected games, latest first
+
+/statline takes league and the same selectors as the statline command,
+game_id, date, vs and last, and picks the latest game when none are given.`
+
+func serveCmd(args []string) error {
+	flags := newFlagSet("serve", "[flags]", serveUsage)
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	if help, err := parseFlags(flags, args); help || err != nil {
+		return err
+	}
+	if err := openStore(true); err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /jobs", func(w http.ResponseWriter, r *http.Request) {
+		jobs, err := store.Jobs()
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, jobs)
+	})
+	mux.HandleFunc("GET /uploads", func(w http.ResponseWriter, r *http.Request) {
+		uploads, err := store.Uploads()
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, uploads)
+	})
+	mux.HandleFunc("GET /statline", serveStatline)
+
+	fmt.Printf("listening on http://%s\n", *addr)
+	return http.ListenAndServe(*addr, mux)
+}
+
+type statlineResponse struct {
+	Title    string                   `json:"title"`
+	Statline string                   `json:"statline"`
+	Game     nba.LeagueGameFinderGame `json:"game"`
+}
+
+func serveStatline(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	player := query.Get("player")
+	if player == "" {
+		writeJSONError(w, http.StatusBadRequest, fmt.Errorf("player is required"))
+		return
+	}
+	l := nba.LeagueNBA
+	if name := query.Get("league"); name != "" {
+		var err error
+		if l, err = nba.ParseLeague(name); err != nil {
+			writeJSONError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	selector := GameSelector{GameID: query.Get("game_id"), Date: query.Get("date"), Vs: query.Get("vs")}
+	if last := query.Get("last"); last != "" {
+		var err error
+		if selector.Last, err = strconv.Atoi(last); err != nil {
+			writeJSONError(w, http.StatusBadRequest, fmt.Errorf("invalid last %q", last))
+			return
+		}
+	}
+	if err := selector.Validate(); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	id, err := store.PlayerIDFromCode(l, player)
+	if err != nil {
+		writeJSONError(w, http.StatusNotFound, err)
+		return
+	}
+	games, err := nba.LeagueGameFinderByPlayerID(l, id)
+	if err != nil {
+		writeJSONError(w, http.StatusBadGateway, err)
+		return
+	}
+	recordGames(games)
+	selected, err := selector.Select(games)
+	if err != nil {
+		writeJSONError(w, http.StatusNotFound, fmt.Errorf("%s: %v", player, err))
+		return
+	}
+
+	res := []statlineResponse{}
+	logs := map[string][]nba.PlayerGameLogGame{}
+	for _, game := range selected {
+		title, err := title(game)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err)
+			return
+		}
+		log, ok := logs[*game.SeasonID]
+		if !ok {
+			log = seasonGameLog(game)
+			logs[*game.SeasonID] = log
+		}
+		statline, err := statString(game, log)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err)
+			return
+		}
+		res = append(res, statlineResponse{Title: title, Statline: statline, Game: game})
+	}
+	writeJSON(w, res)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		fmt.Println("failed to write response:", err)
+	}
+}
+
+func writeJSONError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
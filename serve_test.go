@@ -0,0 +1,27 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServeStatlineBadRequests(t *testing.T) {
+	tests := []struct {
+		query string
+	}{
+		{""},
+		{"?player=Jalen+Brunson&league=nhl"},
+		{"?player=Jalen+Brunson&last=two"},
+		{"?player=Jalen+Brunson&last=-1"},
+		{"?player=Jalen+Brunson&date=yesterday"},
+		{"?player=Jalen+Brunson&date=2025-02-01..2025-01-01"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		serveStatline(w, httptest.NewRequest("GET", "/statline"+tt.query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%q: got status %d, want %d: %s", tt.query, w.Code, http.StatusBadRequest, w.Body)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package shotchart
+
+import (
+	"basketball/nba"
+
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"strings"
+)
+
+// Court coordinates are the ones shotchartdetail uses: tenths of a foot, with
+// the hoop at the origin, x running along the baseline and y running toward
+// half court. The baseline is at y = -47.5 and half court at y = 422.5.
+const (
+	courtLeft   = -250.0
+	courtRight  = 250.0
+	courtBottom = -47.5
+	courtTop    = 422.5
+)
+
+type Shot struct {
+	X    float64
+	Y    float64
+	Made bool
+}
+
+func FromDetail(detail []nba.ShotChartDetailShot) []Shot {
+	shots := make([]Shot, 0, len(detail))
+	for _, s := range detail {
+		if s.LocX == nil || s.LocY == nil {
+			continue
+		}
+		shots = append(shots, Shot{X: *s.LocX, Y: *s.LocY, Made: s.Made()})
+	}
+	return shots
+}
+
+type point struct{ x, y float64 }
+
+// court is the half court's markings as polylines in court coordinates, so
+// the SVG and PNG renderers draw exactly the same lines.
+var court = func() [][]point {
+	lines := [][]point{
+		// boundary
+		{{courtLeft, courtBottom}, {courtRight, courtBottom}, {courtRight, courtTop}, {courtLeft, courtTop}, {courtLeft, courtBottom}},
+		// outer and inner paint
+		{{-80, courtBottom}, {-80, 142.5}, {80, 142.5}, {80, courtBottom}},
+		{{-60, courtBottom}, {-60, 142.5}},
+		{{60, courtBottom}, {60, 142.5}},
+		// backboard
+		{{-30, -7.5}, {30, -7.5}},
+		// three point corners
+		{{-220, courtBottom}, {-220, 89.5}},
+		{{220, courtBottom}, {220, 89.5}},
+	}
+	corner := math.Acos(220 / 237.5)
+	lines = append(lines,
+		arc(0, 0, 7.5, 0, 2*math.Pi),             // hoop
+		arc(0, 0, 40, 0, math.Pi),                // restricted area
+		arc(0, 0, 237.5, corner, math.Pi-corner), // three point line
+		arc(0, 142.5, 60, 0, math.Pi),            // free throw circle
+		arc(0, courtTop, 60, math.Pi, 2*math.Pi), // center circle
+		arc(0, courtTop, 20, math.Pi, 2*math.Pi), // inner center circle
+	)
+	return lines
+}()
+
+func arc(cx, cy, r, from, to float64) []point {
+	steps := int(math.Max(12, r*(to-from)/4))
+	points := make([]point, steps+1)
+	for i := range points {
+		theta := from + (to-from)*float64(i)/float64(steps)
+		points[i] = point{cx + r*math.Cos(theta), cy + r*math.Sin(theta)}
+	}
+	return points
+}
+
+// transform maps court coordinates onto a width x height canvas, fitting the
+// court to the canvas and centering it.
+type transform struct {
+	scale   float64
+	offsetX float64
+	offsetY float64
+}
+
+func newTransform(width, height int) transform {
+	courtWidth, courtHeight := courtRight-courtLeft, courtTop-courtBottom
+	scale := math.Min(float64(width)/courtWidth, float64(height)/courtHeight)
+	return transform{
+		scale:   scale,
+		offsetX: (float64(width) - courtWidth*scale) / 2,
+		offsetY: (float64(height) - courtHeight*scale) / 2,
+	}
+}
+
+// apply puts the baseline at the top of the canvas, the way nba.com draws
+// shot charts.
+func (t transform) apply(p point) point {
+	return point{
+		x: t.offsetX + (p.x-courtLeft)*t.scale,
+		y: t.offsetY + (p.y-courtBottom)*t.scale,
+	}
+}
+
+const (
+	background = "#f4e4c8"
+	lineColor  = "#3b3b3b"
+	madeColor  = "#2e8b57"
+	missColor  = "#c0392b"
+)
+
+// WriteSVG draws the shots on a half court with the title along the bottom.
+func WriteSVG(w io.Writer, shots []Shot, title string, width, height int) error {
+	t := newTransform(width, height)
+	b := &strings.Builder{}
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", width, height, width, height)
+	fmt.Fprintf(b, `<rect width="%d" height="%d" fill="%s"/>`+"\n", width, height, background)
+	stroke := math.Max(1, 2*t.scale)
+	for _, line := range court {
+		coords := make([]string, len(line))
+		for i, p := range line {
+			p = t.apply(p)
+			coords[i] = fmt.Sprintf("%.1f,%.1f", p.x, p.y)
+		}
+		fmt.Fprintf(b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="%.1f"/>`+"\n", strings.Join(coords, " "), lineColor, stroke)
+	}
+	r := 6 * t.scale
+	for _, s := range shots {
+		p := t.apply(point{s.X, s.Y})
+		if s.Made {
+			fmt.Fprintf(b, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="%s" fill-opacity="0.85"/>`+"\n", p.x, p.y, r, madeColor)
+		} else {
+			fmt.Fprintf(b, `<path d="M%.1f %.1fL%.1f %.1fM%.1f %.1fL%.1f %.1f" stroke="%s" stroke-width="%.1f"/>`+"\n",
+				p.x-r, p.y-r, p.x+r, p.y+r, p.x-r, p.y+r, p.x+r, p.y-r, missColor, stroke*1.5)
+		}
+	}
+	if title != "" {
+		fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="middle" font-family="Helvetica, Arial, sans-serif" font-size="%d" fill="%s">%s</text>`+"\n",
+			width/2, height-height/20, height/24, lineColor, html.EscapeString(title))
+	}
+	b.WriteString("</svg>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WritePNG draws the same chart as WriteSVG as a PNG. There is no font to draw
+// the title with, so the PNG has no title.
+func WritePNG(w io.Writer, shots []Shot, width, height int) error {
+	t := newTransform(width, height)
+	c := &canvas{image.NewRGBA(image.Rect(0, 0, width, height))}
+	c.fill(hexColor(background))
+
+	stroke := math.Max(1, 2*t.scale)
+	for _, line := range court {
+		for i := 1; i < len(line); i++ {
+			c.line(t.apply(line[i-1]), t.apply(line[i]), stroke, hexColor(lineColor))
+		}
+	}
+	r := 6 * t.scale
+	for _, s := range shots {
+		p := t.apply(point{s.X, s.Y})
+		if s.Made {
+			c.disc(p, r, hexColor(madeColor))
+		} else {
+			c.line(point{p.x - r, p.y - r}, point{p.x + r, p.y + r}, stroke*1.5, hexColor(missColor))
+			c.line(point{p.x - r, p.y + r}, point{p.x + r, p.y - r}, stroke*1.5, hexColor(missColor))
+		}
+	}
+	return png.Encode(w, c.img)
+}
+
+// Save writes the chart as basePath.svg and basePath.png and returns the path
+// of the PNG. The PNG is 1280x720 so it can be used as a YouTube thumbnail.
+func Save(basePath string, shots []Shot, title string) (string, error) {
+	err := writeFile(basePath+".svg", func(w io.Writer) error {
+		return WriteSVG(w, shots, title, 1280, 720)
+	})
+	if err != nil {
+		return "", err
+	}
+	pngPath := basePath + ".png"
+	err = writeFile(pngPath, func(w io.Writer) error {
+		return WritePNG(w, shots, 1280, 720)
+	})
+	if err != nil {
+		return "", err
+	}
+	return pngPath, nil
+}
+
+// writeFile creates path and writes it with write. A failed write or close
+// is returned, since either can leave the file short.
+func writeFile(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+type canvas struct {
+	img *image.RGBA
+}
+
+func (c *canvas) fill(col color.RGBA) {
+	for i := 0; i < len(c.img.Pix); i += 4 {
+		c.img.Pix[i], c.img.Pix[i+1], c.img.Pix[i+2], c.img.Pix[i+3] = col.R, col.G, col.B, col.A
+	}
+}
+
+func (c *canvas) disc(center point, r float64, col color.RGBA) {
+	for y := int(center.y - r); y <= int(center.y+r)+1; y++ {
+		for x := int(center.x - r); x <= int(center.x+r)+1; x++ {
+			dx, dy := float64(x)+0.5-center.x, float64(y)+0.5-center.y
+			if dx*dx+dy*dy <= r*r {
+				c.img.SetRGBA(x, y, col)
+			}
+		}
+	}
+}
+
+// line stamps discs along the segment, which is plenty for court markings.
+func (c *canvas) line(from, to point, width float64, col color.RGBA) {
+	length := math.Hypot(to.x-from.x, to.y-from.y)
+	steps := int(math.Max(1, length*2))
+	for i := 0; i <= steps; i++ {
+		f := float64(i) / float64(steps)
+		c.disc(point{from.x + (to.x-from.x)*f, from.y + (to.y-from.y)*f}, width/2, col)
+	}
+}
+
+func hexColor(s string) color.RGBA {
+	var r, g, b uint8
+	fmt.Sscanf(s, "#%02x%02x%02x", &r, &g, &b)
+	return color.RGBA{r, g, b, 255}
+}
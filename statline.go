@@ -0,0 +1,245 @@
+package main
+
+import (
+	"basketball/nba"
+
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type StatlineFormat string
+
+const (
+	StatlineText     StatlineFormat = "text"
+	StatlineJSON     StatlineFormat = "json"
+	StatlineCSV      StatlineFormat = "csv"
+	StatlineMarkdown StatlineFormat = "markdown"
+)
+
+func ParseStatlineFormat(s string) (StatlineFormat, error) {
+	switch f := StatlineFormat(strings.ToLower(s)); f {
+	case StatlineText, StatlineJSON, StatlineCSV, StatlineMarkdown:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown format %q, expected text, json, csv or markdown", s)
+}
+
+// StatlineRow is a game finder row plus what we work out from it. The game's
+// fields are embedded so JSON and CSV output keep the game finder's names.
+type StatlineRow struct {
+	nba.LeagueGameFinderGame
+	Title    string
+	Statline string
+	Opponent string
+	Home     bool
+	// TSPct is true shooting: points per two shooting possessions, counting
+	// free throw trips as 0.44 of a possession.
+	TSPct *float64
+	// EFGPct is field goal percentage with threes worth one and a half makes.
+	EFGPct *float64
+	// GameScore is John Hollinger's single number summary of a box score.
+	GameScore    float64
+	DoubleDouble bool
+	TripleDouble bool
+}
+
+func newStatlineRow(game nba.LeagueGameFinderGame, log []nba.PlayerGameLogGame) (StatlineRow, error) {
+	row := StatlineRow{LeagueGameFinderGame: game}
+	var err error
+	if row.Title, err = title(game); err != nil {
+		return row, err
+	}
+	if row.Statline, err = statString(game, log); err != nil {
+		return row, err
+	}
+	if game.Matchup != nil {
+		row.Opponent = opponent(*game.Matchup)
+		row.Home = strings.Contains(*game.Matchup, "vs.")
+	}
+
+	pts, fgm, fga, fg3m := deref(game.PTS), deref(game.FGM), deref(game.FGA), deref(game.FG3M)
+	ftm, fta := deref(game.FTM), deref(game.FTA)
+	if shots := fga + 0.44*fta; shots > 0 {
+		ts := pts / (2 * shots)
+		row.TSPct = &ts
+	}
+	if fga > 0 {
+		efg := (fgm + 0.5*fg3m) / fga
+		row.EFGPct = &efg
+	}
+	gameScore := pts + 0.4*fgm - 0.7*fga - 0.4*(fta-ftm) +
+		0.7*deref(game.OREB) + 0.3*deref(game.DREB) + deref(game.STL) +
+		0.7*deref(game.AST) + 0.7*deref(game.BLK) - 0.4*deref(game.PF) - deref(game.TOV)
+	row.GameScore = math.Round(gameScore*10) / 10
+
+	doubles := 0
+	for _, s := range []*float64{game.PTS, game.REB, game.AST, game.STL, game.BLK} {
+		if deref(s) >= 10 {
+			doubles++
+		}
+	}
+	row.DoubleDouble = doubles >= 2
+	row.TripleDouble = doubles >= 3
+	return row, nil
+}
+
+// Statline prints the selected games of each player. Players that can't be
+// found are reported and skipped so one typo doesn't sink a whole batch.
+func Statline(playerCodes []string, selector GameSelector, format StatlineFormat) error {
+	rows := []StatlineRow{}
+	failed := 0
+	for _, playerCode := range playerCodes {
+		games, err := playerGames(playerCode, selector)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			failed++
+			continue
+		}
+		// the games are usually from one season, so one game log serves them all
+		logs := map[string][]nba.PlayerGameLogGame{}
+		for _, game := range games {
+			log, ok := logs[*game.SeasonID]
+			if !ok {
+				log = seasonGameLog(game)
+				logs[*game.SeasonID] = log
+			}
+			row, err := newStatlineRow(game, log)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", playerCode, err)
+				failed++
+				continue
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	if err := writeStatlines(os.Stdout, rows, format); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d players failed", failed, len(playerCodes))
+	}
+	return nil
+}
+
+func writeStatlines(w io.Writer, rows []StatlineRow, format StatlineFormat) error {
+	switch format {
+	case StatlineJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	case StatlineCSV:
+		return writeStatlinesCSV(w, rows)
+	case StatlineMarkdown:
+		return writeStatlinesMarkdown(w, rows)
+	default:
+		for i, row := range rows {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintln(w, "GameID:", *row.GameID)
+			fmt.Fprintln(w, "PlayerID:", int(*row.PlayerId))
+			fmt.Fprintln(w, row.Title)
+			fmt.Fprintln(w, row.Statline)
+		}
+		return nil
+	}
+}
+
+// statlineFields flattens a row into the same names JSON uses, so a CSV
+// header lines up with the JSON keys.
+func statlineFields(row StatlineRow) (names, values []string) {
+	var walk func(v reflect.Value)
+	walk = func(v reflect.Value) {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Anonymous {
+				walk(v.Field(i))
+				continue
+			}
+			names = append(names, f.Name)
+			values = append(values, formatField(v.Field(i)))
+		}
+	}
+	walk(reflect.ValueOf(row))
+	return names, values
+}
+
+func formatField(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	default:
+		return v.String()
+	}
+}
+
+func writeStatlinesCSV(w io.Writer, rows []StatlineRow) error {
+	cw := csv.NewWriter(w)
+	names, _ := statlineFields(StatlineRow{})
+	if err := cw.Write(names); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		_, values := statlineFields(row)
+		if err := cw.Write(values); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeStatlinesMarkdown writes a table for pasting into chat, so it has the
+// box score columns people read rather than every field.
+func writeStatlinesMarkdown(w io.Writer, rows []StatlineRow) error {
+	header := []string{"Player", "Date", "Matchup", "W/L", "MIN", "PTS", "REB", "AST", "STL", "BLK", "TOV", "FG", "3PT", "FT", "+/-", "TS%", "GmSc"}
+	fmt.Fprintf(w, "| %s |\n", strings.Join(header, " | "))
+	fmt.Fprintf(w, "|%s\n", strings.Repeat(" --- |", len(header)))
+	for _, row := range rows {
+		ts := ""
+		if row.TSPct != nil {
+			ts = fmt.Sprintf("%.1f%%", *row.TSPct*100)
+		}
+		cells := []string{
+			deref(row.PlayerName),
+			deref(row.GameDate),
+			deref(row.Matchup),
+			deref(row.WL),
+			fmt.Sprint(int(deref(row.MIN))),
+			fmt.Sprint(int(deref(row.PTS))),
+			fmt.Sprint(int(deref(row.REB))),
+			fmt.Sprint(int(deref(row.AST))),
+			fmt.Sprint(int(deref(row.STL))),
+			fmt.Sprint(int(deref(row.BLK))),
+			fmt.Sprint(int(deref(row.TOV))),
+			fmt.Sprintf("%d-%d", int(deref(row.FGM)), int(deref(row.FGA))),
+			fmt.Sprintf("%d-%d", int(deref(row.FG3M)), int(deref(row.FG3A))),
+			fmt.Sprintf("%d-%d", int(deref(row.FTM)), int(deref(row.FTA))),
+			fmt.Sprintf("%+d", int(deref(row.PlusMinus))),
+			ts,
+			fmt.Sprintf("%.1f", row.GameScore),
+		}
+		for i, c := range cells {
+			cells[i] = strings.ReplaceAll(c, "|", `\|`)
+		}
+		fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"basketball/nba"
+
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+// boxScore is a full game finder row: 30 points on 11-20 shooting with 4-8
+// from three and 4-5 from the line, 10 rebounds and 8 assists.
+func boxScore() nba.LeagueGameFinderGame {
+	return nba.LeagueGameFinderGame{
+		SeasonID:         ptr("22024"),
+		PlayerId:         ptr(1628973.0),
+		PlayerName:       ptr("Jalen Brunson"),
+		TeamID:           ptr(1610612752.0),
+		TeamAbbreviation: ptr("NYK"),
+		TeamName:         ptr("New York Knicks"),
+		GameID:           ptr("0022400001"),
+		GameDate:         ptr("2024-10-22"),
+		Matchup:          ptr("NYK @ BOS"),
+		WL:               ptr("L"),
+		MIN:              ptr(36.0),
+		PTS:              ptr(30.0),
+		FGM:              ptr(11.0),
+		FGA:              ptr(20.0),
+		FG_PCT:           ptr(0.55),
+		FG3M:             ptr(4.0),
+		FG3A:             ptr(8.0),
+		FG3_PCT:          ptr(0.5),
+		FTM:              ptr(4.0),
+		FTA:              ptr(5.0),
+		FT_PCT:           ptr(0.8),
+		OREB:             ptr(2.0),
+		DREB:             ptr(8.0),
+		REB:              ptr(10.0),
+		AST:              ptr(8.0),
+		STL:              ptr(1.0),
+		BLK:              ptr(0.0),
+		TOV:              ptr(3.0),
+		PF:               ptr(2.0),
+		PlusMinus:        ptr(-9.0),
+	}
+}
+
+func TestNewStatlineRow(t *testing.T) {
+	tripleDouble := boxScore()
+	tripleDouble.Matchup = ptr("NYK vs. BOS")
+	tripleDouble.AST = ptr(10.0)
+	noShots := boxScore()
+	noShots.PTS, noShots.FGM, noShots.FGA, noShots.FG3M, noShots.FG3A, noShots.FTM, noShots.FTA = ptr(0.0), ptr(0.0), ptr(0.0), ptr(0.0), ptr(0.0), ptr(0.0), ptr(0.0)
+
+	tests := []struct {
+		name                       string
+		game                       nba.LeagueGameFinderGame
+		opponent                   string
+		home                       bool
+		ts, efg                    float64 // NaN when there's nothing to divide by
+		gameScore                  float64
+		doubleDouble, tripleDouble bool
+	}{
+		{"double double on the road", boxScore(), "BOS", false, 30 / (2 * 22.2), 13.0 / 20, 26.6, true, false},
+		{"triple double at home", tripleDouble, "BOS", true, 30 / (2 * 22.2), 13.0 / 20, 28, true, true},
+		{"no shots", noShots, "BOS", false, math.NaN(), math.NaN(), 6.6, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row, err := newStatlineRow(tt.game, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if row.Title != "Jalen Brunson | "+*tt.game.Matchup+" 10.22.2024" {
+				t.Errorf("got title %q", row.Title)
+			}
+			if row.Opponent != tt.opponent || row.Home != tt.home {
+				t.Errorf("got opponent %q, home %v", row.Opponent, row.Home)
+			}
+			checkPct := func(name string, got *float64, want float64) {
+				t.Helper()
+				switch {
+				case math.IsNaN(want) && got != nil:
+					t.Errorf("got %s %v, want none", name, *got)
+				case !math.IsNaN(want) && (got == nil || math.Abs(*got-want) > 1e-9):
+					t.Errorf("got %s %v, want %v", name, got, want)
+				}
+			}
+			checkPct("TS%", row.TSPct, tt.ts)
+			checkPct("eFG%", row.EFGPct, tt.efg)
+			if row.GameScore != tt.gameScore {
+				t.Errorf("got game score %v, want %v", row.GameScore, tt.gameScore)
+			}
+			if row.DoubleDouble != tt.doubleDouble || row.TripleDouble != tt.tripleDouble {
+				t.Errorf("got double double %v, triple double %v", row.DoubleDouble, row.TripleDouble)
+			}
+		})
+	}
+}
+
+func TestWriteStatlinesCSV(t *testing.T) {
+	row, err := newStatlineRow(boxScore(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	noShots := row
+	noShots.TSPct, noShots.EFGPct = nil, nil
+	noShots.PlayerName = ptr(`Josh "Hart", Jr.`)
+
+	b := bytes.Buffer{}
+	if err := writeStatlines(&b, []StatlineRow{row, noShots}, StatlineCSV); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&b).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want a header and 2 rows", len(records))
+	}
+
+	// the header uses the same names as the JSON keys
+	j, err := json.Marshal(row)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := map[string]any{}
+	if err := json.Unmarshal(j, &keys); err != nil {
+		t.Fatal(err)
+	}
+	header := records[0]
+	if len(header) != len(keys) {
+		t.Errorf("got %d columns, JSON has %d keys", len(header), len(keys))
+	}
+	for _, name := range header {
+		if _, ok := keys[name]; !ok {
+			t.Errorf("column %q isn't a JSON key", name)
+		}
+	}
+
+	tests := []struct {
+		record int
+		column string
+		want   string
+	}{
+		{1, "GameID", "0022400001"},
+		{1, "PlayerName", "Jalen Brunson"},
+		{1, "PTS", "30"},
+		{1, "FG_PCT", "0.55"},
+		{1, "PlusMinus", "-9"},
+		{1, "Title", "Jalen Brunson | NYK @ BOS 10.22.2024"},
+		{1, "Opponent", "BOS"},
+		{1, "Home", "false"},
+		{1, "EFGPct", "0.65"},
+		{1, "GameScore", "26.6"},
+		{1, "DoubleDouble", "true"},
+		{1, "TripleDouble", "false"},
+		{2, "PlayerName", `Josh "Hart", Jr.`},
+		{2, "TSPct", ""},
+		{2, "EFGPct", ""},
+	}
+	for _, tt := range tests {
+		i := indexOf(header, tt.column)
+		if i < 0 {
+			t.Errorf("no %s column", tt.column)
+			continue
+		}
+		if got := records[tt.record][i]; got != tt.want {
+			t.Errorf("row %d %s = %q, want %q", tt.record, tt.column, got, tt.want)
+		}
+	}
+}
+
+func indexOf(s []string, v string) int {
+	for i, x := range s {
+		if x == v {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestWriteStatlinesMarkdown(t *testing.T) {
+	row, err := newStatlineRow(boxScore(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	piped := row
+	piped.PlayerName = ptr("Jalen | Brunson")
+	piped.TSPct = nil
+	piped.PlusMinus = ptr(12.0)
+
+	b := bytes.Buffer{}
+	if err := writeStatlines(&b, []StatlineRow{row, piped}, StatlineMarkdown); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"| Player | Date | Matchup | W/L | MIN | PTS | REB | AST | STL | BLK | TOV | FG | 3PT | FT | +/- | TS% | GmSc |",
+		"| --- | --- | --- | --- | --- | --- | --- | --- | --- | --- | --- | --- | --- | --- | --- | --- | --- |",
+		"| Jalen Brunson | 2024-10-22 | NYK @ BOS | L | 36 | 30 | 10 | 8 | 1 | 0 | 3 | 11-20 | 4-8 | 4-5 | -9 | 67.6% | 26.6 |",
+		`| Jalen \| Brunson | 2024-10-22 | NYK @ BOS | L | 36 | 30 | 10 | 8 | 1 | 0 | 3 | 11-20 | 4-8 | 4-5 | +12 |  | 26.6 |`,
+	}
+	got := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(got) != len(want) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(got), len(want), b.String())
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d:\ngot  %s\nwant %s", i+1, got[i], want[i])
+		}
+	}
+}
+
+func TestParseStatlineFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    StatlineFormat
+		wantErr bool
+	}{
+		{"text", StatlineText, false},
+		{"JSON", StatlineJSON, false},
+		{"csv", StatlineCSV, false},
+		{"markdown", StatlineMarkdown, false},
+		{"md", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseStatlineFormat(tt.in)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseStatlineFormat(%q) = %q, %v", tt.in, got, err)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"os"
 	"runtime"
 )
 
@@ -9,3 +10,13 @@ func ErrorWithTrace(e error) error {
 	_, file, line, _ := runtime.Caller(1)
 	return fmt.Errorf("%s:%d\n\t%v", file, line, e)
 }
+
+// IsTerminal reports whether f is a terminal someone could answer a prompt
+// on, as opposed to a pipe, a file or /dev/null under cron.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"basketball/db"
+	"basketball/nba"
+
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const watchUsage = `Polls the live scoreboard for the team's games. Once a game is final it waits
+for the stats to be published and for every player's clips to add up to their
+box score, then makes the team's reels and, with --upload, uploads them.
+
+Jobs are stored as they go, so a watch that's restarted picks up where the
+last one left off.`
+
+func watchCmd(args []string) error {
+	flags := newFlagSet("watch", "[flags]", watchUsage)
+	leagueName := leagueFlag(flags)
+	team := flags.String("team", "", "team to watch, by tricode, name or id (default NYK, NYL or WES for the league)")
+	opts := WatchOptions{}
+	flags.DurationVar(&opts.Interval, "interval", time.Minute, "how often to poll the scoreboard")
+	flags.DurationVar(&opts.ClipInterval, "clip-interval", 5*time.Minute, "how often to check for stats and clips once a game is final")
+	flags.DurationVar(&opts.ClipTimeout, "clip-timeout", 3*time.Hour, "how long to wait for clips to add up before making the reels anyway")
+	flags.BoolVar(&opts.Once, "once", false, "exit once the first final game's reels are made")
+	upload := flags.Bool("upload", false, "upload the reels once they're rendered")
+	forceFlag(flags)
+	policy := flags.String("on-mismatch", string(MismatchWarn), "once --clip-timeout has passed, what to do with players whose clips still don't add up: warn, fail or skip")
+	collision := outputFlags(flags)
+	if help, err := parseFlags(flags, args); help || err != nil {
+		return err
+	}
+	if err := setOutput(*collision); err != nil {
+		return err
+	}
+	if err := setMismatchPolicy(*policy); err != nil {
+		return err
+	}
+	if mismatchPolicy == MismatchPrompt {
+		return usageErrorf("watch runs unattended, so --on-mismatch can't be prompt")
+	}
+	if opts.Interval <= 0 || opts.ClipInterval <= 0 {
+		return usageErrorf("--interval and --clip-interval must be positive")
+	}
+	if err := setLeague(*leagueName); err != nil {
+		return err
+	}
+	if *team == "" {
+		*team = homeTeams[league]
+	}
+	// nobody is around to answer prompts
+	assumeYes = true
+	noUpload = !*upload
+	if err := openStore(true); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return Watch(ctx, *team, opts)
+}
+
+type WatchOptions struct {
+	Interval     time.Duration
+	ClipInterval time.Duration
+	ClipTimeout  time.Duration
+	Once         bool
+}
+
+// Watch makes the team's reels as each of its games goes final, until ctx is
+// done. A game that fails is logged and watching carries on.
+func Watch(ctx context.Context, teamQuery string, opts WatchOptions) error {
+	team, err := resolveTeam(teamQuery)
+	if err != nil {
+		return err
+	}
+	if team.Abbreviation == "" {
+		return fmt.Errorf("the %s have no tricode to find them on the scoreboard with, run basketball sync first", team.Name)
+	}
+	log.Printf("watching the %s", team.Name)
+
+	handled := map[string]bool{}
+	for {
+		gameID, err := waitForFinal(ctx, team, handled, opts.Interval)
+		if err != nil {
+			if ctx.Err() != nil {
+				log.Println("stopped watching")
+				return nil
+			}
+			return err
+		}
+		err = watchGame(ctx, team, gameID, opts)
+		if ctx.Err() != nil {
+			log.Println("stopped watching")
+			return nil
+		}
+		// a game with players still missing is picked up again on the next
+		// poll, every other game is done with whether it worked or not
+		if !errors.Is(err, errPlayersMissing) {
+			handled[gameID] = true
+		}
+		if err != nil {
+			log.Printf("%s: %v", gameID, err)
+		} else {
+			log.Printf("%s: reels made", gameID)
+		}
+		if opts.Once {
+			return err
+		}
+	}
+}
+
+// waitForFinal polls the scoreboard until one of the team's games that hasn't
+// been handled yet is final.
+func waitForFinal(ctx context.Context, team db.Team, handled map[string]bool, interval time.Duration) (string, error) {
+	lastStatus := ""
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		scoreboard, err := nba.LiveScoreboard(ctx, league)
+		if err != nil && ctx.Err() == nil {
+			log.Println("failed to poll the scoreboard:", err)
+		}
+		if scoreboard != nil {
+			status := "no game today"
+			for _, g := range scoreboard.Games {
+				if !g.HasTeam(team.Abbreviation) || g.GameId == nil || handled[*g.GameId] {
+					continue
+				}
+				if g.Status() == nba.GameStatusFinal {
+					log.Printf("%s: %s", *g.GameId, deref(g.GameStatusText))
+					return *g.GameId, nil
+				}
+				status = fmt.Sprintf("%s: %s %s", *g.GameId, deref(g.GameCode), deref(g.GameStatusText))
+			}
+			if status != lastStatus {
+				log.Println(status)
+				lastStatus = status
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// errPlayersMissing is returned when some of a game's players still had no
+// game log row once the clip timeout passed, so the game is tried again.
+var errPlayersMissing = errors.New("players missing")
+
+// watchGame waits for a final game's stats and clips to be published and then
+// makes its reels.
+func watchGame(ctx context.Context, team db.Team, gameID string, opts WatchOptions) error {
+	var game nba.LeagueGameFinderGame
+	err := pollUntil(ctx, opts.ClipInterval, "stats", func() (bool, error) {
+		var found bool
+		err := catch(func() error {
+			season, err := nba.SeasonFromGameID(gameID)
+			if err != nil {
+				return err
+			}
+			games := nba.LeagueGameFinderByTeamID(league, team.ID, season)
+			recordGames(games)
+			game, found = findGame(games, gameID)
+			return nil
+		})
+		return found, err
+	})
+	if err != nil {
+		return err
+	}
+
+	// players' game logs can lag the team's, so wait until everyone who got in
+	// has a job, but only as long as we'd wait for clips
+	deadline := time.Now().Add(opts.ClipTimeout)
+	var jobs []*db.Job
+	var missing []string
+	err = pollUntil(ctx, opts.ClipInterval, "players", func() (bool, error) {
+		err := catch(func() error {
+			var err error
+			jobs, missing, err = queueTeamGame(team.ID, game, HighlightsRecipe, map[int][]nba.LeagueGameFinderGame{})
+			return err
+		})
+		if err != nil {
+			return false, err
+		}
+		if len(missing) > 0 && time.Now().Before(deadline) {
+			return false, fmt.Errorf("no game log yet for %s", strings.Join(missing, ", "))
+		}
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+	var missingErr error
+	if len(missing) > 0 {
+		log.Printf("%s: gave up waiting for %s after %s", gameID, strings.Join(missing, ", "), opts.ClipTimeout)
+		missingErr = fmt.Errorf("%w: %s", errPlayersMissing, strings.Join(missing, ", "))
+	}
+	if len(jobs) == 0 {
+		if missingErr == nil {
+			log.Printf("%s: reels already made", gameID)
+		}
+		return missingErr
+	}
+
+	err = pollUntil(ctx, opts.ClipInterval, "clips", func() (bool, error) {
+		for _, job := range jobs {
+			if job.Stage != db.JobStageFetch {
+				continue
+			}
+			if err := catch(func() error { return clipsAddUp(job) }); err != nil {
+				if time.Now().After(deadline) {
+					log.Printf("gave up waiting for clips after %s, making the reels anyway", opts.ClipTimeout)
+					return true, nil
+				}
+				return false, fmt.Errorf("%s: %v", job.PlayerName, err)
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := catch(func() error { return runTeamJobs(jobs) }); err != nil {
+		return err
+	}
+	return missingErr
+}
+
+// pollUntil calls ready every interval until it reports true or ctx is done.
+// Errors are logged as the reason it isn't ready yet.
+func pollUntil(ctx context.Context, interval time.Duration, what string, ready func() (bool, error)) error {
+	for {
+		ok, err := ready()
+		if ok {
+			return nil
+		}
+		if err != nil {
+			log.Printf("waiting for %s: %v", what, err)
+		} else {
+			log.Printf("waiting for %s", what)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// clipsAddUp checks that every measure of the job's recipe has as many clips
+// as the box score says it should.
+func clipsAddUp(job *db.Job) error {
+	recipe, ok := Recipes[job.Recipe]
+	if !ok {
+		return fmt.Errorf("unknown recipe %q", job.Recipe)
+	}
+	for _, m := range recipe.Measures {
+		if _, err := getVideoAssetsByMeasure(job.Game, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// catch turns a panic into an error, since some nba requests still panic when
+// they fail and a watch shouldn't die over one flaky request. It only catches
+// panics on the calling goroutine, so runJob catches its own stages on the
+// worker goroutines it runs on.
+func catch(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return f()
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
@@ -16,11 +17,14 @@ import (
 	"google.golang.org/api/youtube/v3"
 )
 
-func UploadFile(filepath, title, description, playerName, teamName string, service *youtube.Service) {
+// UploadFile publishes the video at filepath and returns its YouTube video ID.
+// onProgress, if not nil, is called with the bytes sent so far after each
+// chunk.
+func UploadFile(filepath, title, description, playerName, teamName string, service *youtube.Service, onProgress func(sent int64)) (string, error) {
 
 	file, err := os.Open(filepath)
 	if err != nil {
-		panic(err)
+		return "", utils.ErrorWithTrace(err)
 	}
 	defer file.Close()
 
@@ -34,7 +38,7 @@ func UploadFile(filepath, title, description, playerName, teamName string, servi
 
 	// Set the privacy status
 	status := &youtube.VideoStatus{
-		PrivacyStatus:           "public",
+		PrivacyStatus:           config.PrivacyStatus,
 		MadeForKids:             false,
 		SelfDeclaredMadeForKids: false,
 	}
@@ -43,13 +47,33 @@ func UploadFile(filepath, title, description, playerName, teamName string, servi
 		Snippet: snippet,
 		Status:  status,
 	}
-	fmt.Println("Uploading to youtube...")
 	call := service.Videos.Insert([]string{"snippet", "status"}, upload)
-	resp, err := call.Media(file, googleapi.ChunkSize(32*1024*1024)).Do()
+	call = call.Media(file, googleapi.ChunkSize(32*1024*1024))
+	if onProgress != nil {
+		call = call.ProgressUpdater(func(current, total int64) {
+			onProgress(current)
+		})
+	}
+	resp, err := call.Do()
+	if err != nil {
+		return "", utils.ErrorWithTrace(err)
+	}
+	return resp.Id, nil
+}
+
+// SetThumbnail replaces the video's auto-generated thumbnail with the image at
+// filepath. YouTube only allows custom thumbnails on verified channels.
+func SetThumbnail(videoID, filepath string, service *youtube.Service) error {
+	file, err := os.Open(filepath)
 	if err != nil {
-		panic(err)
+		return utils.ErrorWithTrace(err)
 	}
-	fmt.Println("Upload successful :D!", title, resp.Id)
+	defer file.Close()
+
+	if _, err := service.Thumbnails.Set(videoID).Media(file).Do(); err != nil {
+		return utils.ErrorWithTrace(err)
+	}
+	return nil
 }
 
 func GetService() (*youtube.Service, error) {
@@ -122,6 +146,9 @@ func getTokenFromFile(file string) (*oauth2.Token, error) {
 
 func getTokenFromWeb(oauthConfig *oauth2.Config) (*oauth2.Token, error) {
 	authURL := oauthConfig.AuthCodeURL("state-token", oauth2.AccessTypeOffline)
+	if !utils.IsTerminal(os.Stdin) {
+		return nil, fmt.Errorf("no valid youtube token in %s and no terminal to paste an authorization code into; authorize once interactively, e.g. with basketball upload", config.TokenFile)
+	}
 	fmt.Printf("Go to the following link in your browser: \n%v\n", authURL)
 
 	fmt.Printf("Enter authorization code: ")
@@ -138,6 +165,9 @@ func getTokenFromWeb(oauthConfig *oauth2.Config) (*oauth2.Token, error) {
 }
 
 func SaveToken(file string, token *oauth2.Token) {
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		panic(fmt.Errorf("unable to cache OAuth token: %v", err))
+	}
 	f, err := os.OpenFile(file, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
 	if err != nil {
 		panic(fmt.Errorf("unable to cache OAuth token: %v", err))